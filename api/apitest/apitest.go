@@ -0,0 +1,247 @@
+// Package apitest boots the whole API on a migrated in-memory SQLite
+// database, so tests can exercise the HTTP routes end to end.
+package apitest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/database"
+	"github.com/zura-t/bookstore_fiber/jobs"
+	"github.com/zura-t/bookstore_fiber/migrations"
+	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"gorm.io/gorm"
+)
+
+// Password is the password of every user created by the harness.
+const Password = "correct horse battery"
+
+type Harness struct {
+	t      *testing.T
+	App    *fiber.App
+	DB     *gorm.DB
+	Repos  repository.Repositories
+	Config config.Config
+}
+
+// User is a registered user together with an access token for it.
+type User struct {
+	Id       uint
+	Name     string
+	Email    string
+	Password string
+	Token    string
+}
+
+// New starts the API on a fresh database. The working directory is switched
+// to a temporary directory for the duration of the test, because uploads are
+// stored relative to it. Options can adjust the test configuration.
+func New(t *testing.T, options ...func(*config.Config)) *Harness {
+	t.Helper()
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "public", "uploads"), 0o750))
+	require.NoError(t, os.Chdir(dir))
+	t.Cleanup(func() { os.Chdir(wd) })
+
+	config := config.Config{
+		DbDriver:             database.DriverSqlite,
+		DbUrl:                fmt.Sprintf("file:%s?mode=memory&cache=shared&_pragma=foreign_keys(1)", pkg.RandomString(16)),
+		TokenKey:             pkg.RandomString(32),
+		AccessTokenDuration:  15 * time.Minute,
+		RefreshTokenDuration: time.Hour,
+		Argon2Memory:         1024,
+		Argon2Iterations:     1,
+		Argon2Parallelism:    1,
+		PasswordMinLength:    8,
+		PasswordMaxLength:    128,
+		OidcStateDuration:    10 * time.Minute,
+		DeletionGracePeriod:  24 * time.Hour,
+		ExportDir:            filepath.Join(dir, "exports"),
+		ExportTTL:            time.Hour,
+		ExportWorkers:        1,
+		LegacyDeprecatedAt:   time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC),
+		LegacySunset:         time.Date(2027, 4, 30, 0, 0, 0, 0, time.UTC),
+		GraphQLMaxDepth:      10,
+		GraphQLMaxComplexity: 1000,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+
+	cluster, err := database.Connect(config)
+	require.NoError(t, err)
+	t.Cleanup(func() { cluster.Close() })
+	db := cluster.Primary
+
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	repos := repository.NewGormClusterRepositories(db, cluster.Reader)
+	app := api.NewApp(config)
+	exporter := jobs.NewDataExporter(log, db, config)
+	t.Cleanup(func() { exporter.Shutdown(context.Background()) })
+	deps, err := api.NewDependencies(log, config, cluster, repos, exporter)
+	require.NoError(t, err)
+	api.NewRouter(app, log, config, cluster, deps)
+
+	return &Harness{t, app, db, repos, config}
+}
+
+// Request sends a request with body encoded as JSON, unless it is nil or
+// already an io.Reader. A non empty token is sent as a Bearer token.
+func (h *Harness) Request(method, path string, body interface{}, token string) *http.Response {
+	h.t.Helper()
+
+	var reader io.Reader
+	contentType := ""
+	switch b := body.(type) {
+	case nil:
+	case io.Reader:
+		reader = b
+	default:
+		data, err := json.Marshal(b)
+		require.NoError(h.t, err)
+		reader = bytes.NewReader(data)
+		contentType = fiber.MIMEApplicationJSON
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if contentType != "" {
+		req.Header.Set(fiber.HeaderContentType, contentType)
+	}
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	return h.Do(req)
+}
+
+func (h *Harness) Do(req *http.Request) *http.Response {
+	h.t.Helper()
+
+	resp, err := h.App.Test(req, -1)
+	require.NoError(h.t, err)
+	h.t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// Decode reads a JSON response into v after checking its status. It takes t
+// so that it can be used from subtests.
+func Decode(t *testing.T, resp *http.Response, status int, v interface{}) {
+	t.Helper()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, status, resp.StatusCode, string(body))
+	if v != nil {
+		require.NoError(t, json.Unmarshal(body, v), string(body))
+	}
+}
+
+// CreateUser registers a user with a random name and email and logs it in.
+func (h *Harness) CreateUser() User {
+	h.t.Helper()
+
+	user := User{Name: pkg.RandomString(8), Email: pkg.RandomEmail(), Password: Password}
+	resp := h.Request(http.MethodPost, "/register", map[string]string{
+		"name":     user.Name,
+		"email":    user.Email,
+		"password": user.Password,
+	}, "")
+	var registered struct {
+		Id uint `json:"id"`
+	}
+	Decode(h.t, resp, http.StatusOK, &registered)
+	user.Id = registered.Id
+	user.Token = h.Login(user.Email, user.Password)
+	return user
+}
+
+// CreateAuthor creates a user and makes it an author.
+func (h *Harness) CreateAuthor() User {
+	h.t.Helper()
+
+	user := h.CreateUser()
+	resp := h.Request(http.MethodPatch, "/users/author", nil, user.Token)
+	Decode(h.t, resp, http.StatusOK, nil)
+	return user
+}
+
+func (h *Harness) Login(email, password string) string {
+	h.t.Helper()
+
+	resp := h.Request(http.MethodPost, "/login", map[string]string{
+		"email":    email,
+		"password": password,
+	}, "")
+	var session struct {
+		AccessToken string `json:"access_token"`
+	}
+	Decode(h.t, resp, http.StatusOK, &session)
+	return session.AccessToken
+}
+
+// CreateBook stores a book of author directly in the database.
+func (h *Harness) CreateBook(author User) models.Book {
+	h.t.Helper()
+
+	book := models.Book{
+		Title:       pkg.RandomString(10),
+		Description: pkg.RandomString(30),
+		Price:       uint(pkg.RandomInt(1, 100)),
+		AuthorID:    author.Id,
+		File:        "public/uploads/" + pkg.RandomString(8) + ".pdf",
+	}
+	require.NoError(h.t, h.DB.Create(&book).Error)
+	return book
+}
+
+// Multipart builds a multipart/form-data request with the given fields and a
+// single file.
+func (h *Harness) Multipart(method, path string, fields map[string]string, fileField, fileName string, content []byte, token string) *http.Request {
+	h.t.Helper()
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	for name, value := range fields {
+		require.NoError(h.t, writer.WriteField(name, value))
+	}
+	if fileField != "" {
+		part, err := writer.CreateFormFile(fileField, fileName)
+		require.NoError(h.t, err)
+		_, err = part.Write(content)
+		require.NoError(h.t, err)
+	}
+	require.NoError(h.t, writer.Close())
+
+	req := httptest.NewRequest(method, path, &body)
+	req.Header.Set(fiber.HeaderContentType, writer.FormDataContentType())
+	if token != "" {
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+	}
+	return req
+}
@@ -1,12 +1,8 @@
 package book
 
 import (
-	"fmt"
-
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/pkg"
 	"github.com/zura-t/bookstore_fiber/token"
 )
@@ -19,44 +15,17 @@ func (r bookRouter) AddBookToReadList(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-
-	var req *AddBookToReadList
-	if err := c.BodyParser(&req); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(req); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(req, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+	var req = &AddBookToReadList{}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
 	}
 
-	user := &models.User{ID: data.UserId}
-	err := r.db.Model(&models.UserBook{}).Create(&models.UserBook{UserID: user.ID, BookID: req.BookId}).Error
+	err := r.readList.Add(c.UserContext(), data.UserId, req.BookId)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	return c.SendString("Book added to read list")
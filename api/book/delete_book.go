@@ -1,57 +1,27 @@
 package book
 
 import (
-	"fmt"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/pkg"
 	"github.com/zura-t/bookstore_fiber/token"
 )
 
 func (r *bookRouter) DeleteBook(c *fiber.Ctx) error {
 	var req = &BookId{}
-	if err := c.ParamsParser(req); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-
-	validate := validator.New()
-	if err := validate.Struct(req); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(req, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+	if err := pkg.Bind(c, req); err != nil {
+		return err
 	}
 
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
-	var book models.Book
-	err := r.db.Delete(&book, &models.Book{ID: req.Id, AuthorID: data.UserId}).Error
+	err := r.books.Delete(c.UserContext(), data.UserId, req.Id)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	return c.SendString("Book deleted")
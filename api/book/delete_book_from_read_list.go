@@ -1,63 +1,31 @@
 package book
 
 import (
-	"fmt"
-
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/pkg"
 	"github.com/zura-t/bookstore_fiber/token"
 )
 
 type DeleteBookFromReadList struct {
-	BookId uint `uri:"bookid" json:"bookid" validate:"required,min=1"`
+	BookId uint `params:"bookid" validate:"required,min=1"`
 }
 
 func (r *bookRouter) DeleteBookFromReadList(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
 	var req = &DeleteBookFromReadList{}
-	if err := c.ParamsParser(req); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-
-	validate := validator.New()
-	if err := validate.Struct(req); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(req, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+	if err := pkg.Bind(c, req); err != nil {
+		return err
 	}
 
-	user := &models.User{ID: data.UserId}
-
-	err := r.db.Model(user).Association("ReadList").Delete(&models.Book{ID: req.BookId})
+	err := r.readList.Remove(c.UserContext(), data.UserId, req.BookId)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	return c.SendString("Book deleted from read list")
@@ -1,56 +1,31 @@
 package book
 
 import (
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
 	"github.com/zura-t/bookstore_fiber/pkg"
-	"gorm.io/gorm"
+	"github.com/zura-t/bookstore_fiber/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 func (r *bookRouter) DownloadBook(c *fiber.Ctx) error {
 	req := &BookId{}
-	if err := c.ParamsParser(req); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+	if err := pkg.Bind(c, req); err != nil {
+		return err
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(req); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(req, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-
-	var book models.Book
-	err := r.db.First(&book, req.Id).Error
+	book, err := r.books.Get(c.UserContext(), req.Id)
 	if err != nil {
-		if err == gorm.ErrRecordNotFound {
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(err)
-			return c.Status(fiber.StatusNotFound).JSON(pkg.ErrorResponse(err))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	c.Response().Header.Set("Content-Disposition", "attachment; filename=book")
 	content_type := string(c.Request().Header.ContentType())
 	c.Response().Header.Set("Content-Type", content_type)
-	return c.SendFile(book.File)
+	_, span := tracing.Start(c.UserContext(), "storage.send", attribute.String("file.path", book.File))
+	err = c.SendFile(book.File)
+	tracing.End(span, err)
+	if err == nil {
+		r.metrics.Download("book", c.Response().Header.ContentLength())
+	}
+	return err
 }
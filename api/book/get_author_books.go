@@ -1,14 +1,9 @@
 package book
 
 import (
-	"fmt"
-
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
-	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/token"
-	"gorm.io/gorm/clause"
 )
 
 func (r *bookRouter) GetAuthorBooks(c *fiber.Ctx) error {
@@ -18,23 +13,18 @@ func (r *bookRouter) GetAuthorBooks(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
-	var books []models.Book
-	err := r.db.Order(clause.OrderByColumn{
-		Column: clause.Column{Name: "title"},
-	}).Limit(limit).Offset(offset).Find(&books).Where("AuthorID", data.ID).Error
+	books, err := r.books.ListByAuthor(c.UserContext(), data.UserId, limit, offset)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
-	return c.JSON(books)
+	res := make([]*BookResponse, len(books))
+	for index, v := range books {
+		book := ConvertBook(v)
+		res[index] = &book
+	}
+	return c.JSON(res)
 }
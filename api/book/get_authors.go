@@ -2,38 +2,32 @@ package book
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/entity"
 	"github.com/zura-t/bookstore_fiber/pkg"
-	"gorm.io/gorm/clause"
+	"github.com/zura-t/bookstore_fiber/repository"
 )
 
 type GetAuthors struct {
-	Limit     int    `json:"limit"`
-	Offset    int    `json:"offset"`
-	Name      string `json:"name"`
-	OrderDesc bool   `json:"order_desc"`
+	Limit     int    `query:"limit" validate:"min=0"`
+	Offset    int    `query:"offset" validate:"min=0"`
+	Name      string `query:"name"`
+	OrderDesc bool   `query:"order_desc"`
 }
 
 func (r *bookRouter) GetAuthors(c *fiber.Ctx) error {
-	req := &GetAuthors{Limit: 20, Offset: 0, Name: "", OrderDesc: false}
-	if err := c.BodyParser(req); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+	req := &GetAuthors{Limit: 20}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
 	}
 
-	var authors []models.User
-	err := r.db.Preload("AuthorBooks").Where(models.User{IsAuthor: true, Name: req.Name}).Order(clause.OrderByColumn{
-		Column: clause.Column{Name: "name"},
-		Desc:   req.OrderDesc,
-	}).Limit(req.Limit).Offset(req.Offset).Find(&authors).Error
+	authors, err := r.books.ListAuthors(c.UserContext(), repository.AuthorFilter{
+		Limit:     req.Limit,
+		Offset:    req.Offset,
+		Name:      req.Name,
+		OrderDesc: req.OrderDesc,
+	})
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	res := make([]*AuthorsResponse, len(authors))
@@ -51,10 +45,10 @@ type AuthorsResponse struct {
 	BooksQuantity int    `json:"books_quantity"`
 }
 
-func ConvertAuthors(author models.User) AuthorsResponse {
+func ConvertAuthors(author entity.Author) AuthorsResponse {
 	return AuthorsResponse{
-		Id:            author.ID,
+		Id:            author.Id,
 		Name:          author.Name,
-		BooksQuantity: len(author.AuthorBooks),
+		BooksQuantity: author.BooksCount,
 	}
 }
@@ -0,0 +1,51 @@
+package book
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/problem"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/metrics"
+	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+func TestGetBook(t *testing.T) {
+	log := logrus.New()
+	repos := repository.NewMemoryRepositories()
+	maker, err := token.NewJwtMaker(log, pkg.RandomString(32))
+	require.NoError(t, err)
+
+	app := fiber.New(fiber.Config{ErrorHandler: problem.ErrorHandler})
+	services := service.New(log, config.Config{}, repos, pkg.NewPasswordHasher(pkg.Argon2Params{}), pkg.NewPasswordPolicy(0, 0), nil)
+	NewBookRouter(app, log, config.Config{}, services, maker, metrics.New())
+
+	author := &models.User{Name: "Ann", Email: "ann@example.com", IsAuthor: true}
+	require.NoError(t, repos.Users.Create(context.Background(), author))
+	book := &models.Book{Title: "Dune", Price: 10, AuthorID: author.ID}
+	require.NoError(t, repos.Books.Create(context.Background(), book))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf("/books/%d", book.ID), nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var res BookResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&res))
+	require.Equal(t, book.ID, res.Id)
+	require.Equal(t, "Ann", res.AuthorName)
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/books/999", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
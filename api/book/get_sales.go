@@ -0,0 +1,36 @@
+package book
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+type BookSalesResponse struct {
+	BookResponse
+	InCarts     int `json:"in_carts"`
+	InReadLists int `json:"in_read_lists"`
+}
+
+// GetSales reports the demand for the books of the current author. Books
+// can't be bought yet, so it counts the carts and read lists holding them.
+func (r *bookRouter) GetSales(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 20)
+	offset := c.QueryInt("offset", 0)
+
+	data, ok := c.Locals("user").(*token.Payload)
+	if !ok {
+		return apperror.ErrUnauthenticated
+	}
+
+	sales, err := r.books.Sales(c.UserContext(), data.UserId, limit, offset)
+	if err != nil {
+		return err
+	}
+
+	res := make([]BookSalesResponse, len(sales))
+	for i, v := range sales {
+		res[i] = BookSalesResponse{ConvertBook(v.Book), v.InCarts, v.InReadLists}
+	}
+	return c.JSON(res)
+}
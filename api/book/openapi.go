@@ -0,0 +1,36 @@
+package book
+
+import (
+	"mime/multipart"
+	"net/http"
+
+	"github.com/zura-t/bookstore_fiber/api/openapi"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+// uploadBookForm and updateBookForm document the multipart forms, the
+// handlers read the file apart from the bound request.
+type uploadBookForm struct {
+	UploadBook
+	Book *multipart.FileHeader `form:"book" validate:"required"`
+}
+
+type updateBookForm struct {
+	BookUpdate
+	Book *multipart.FileHeader `form:"book" validate:"required"`
+}
+
+// Routes documents the routes registered by NewBookRouter.
+var Routes = []openapi.Route{
+	{Method: http.MethodGet, Path: "/authors", Tag: "books", Summary: "List authors with their number of books", OptionalAuth: true, Scopes: []string{token.ScopeCatalogRead}, Request: GetAuthors{}, Response: []AuthorsResponse{}},
+	{Method: http.MethodGet, Path: "/books", Tag: "books", Summary: "List books", OptionalAuth: true, Scopes: []string{token.ScopeCatalogRead}, Request: GetBooks{}, Response: []BookResponse{}},
+	{Method: http.MethodGet, Path: "/books/:id", Tag: "books", Summary: "Get a book", OptionalAuth: true, Scopes: []string{token.ScopeCatalogRead}, Request: BookId{}, Response: BookResponse{}, Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/readlist", Tag: "read list", Summary: "List the books in the read list", Auth: true, Parameters: openapi.PageParameters, Response: []BookResponse{}},
+	{Method: http.MethodPost, Path: "/readlist", Tag: "read list", Summary: "Add a book to the read list", Auth: true, Request: AddBookToReadList{}, Response: "", Errors: []int{http.StatusNotFound, http.StatusConflict}},
+	{Method: http.MethodDelete, Path: "/readlist/:bookid", Tag: "read list", Summary: "Remove a book from the read list", Auth: true, Request: DeleteBookFromReadList{}, Response: "", Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/books/my/list", Tag: "authoring", Summary: "List the books of the current author", Auth: true, Scopes: []string{token.ScopeBooksManage}, Parameters: openapi.PageParameters, Response: []BookResponse{}},
+	{Method: http.MethodGet, Path: "/books/my/sales", Tag: "authoring", Summary: "Report the demand for the books of the current author", Description: "Books can't be bought yet, so the report counts the carts and read lists holding each book.", Auth: true, Scopes: []string{token.ScopeSalesRead}, Parameters: openapi.PageParameters, Response: []BookSalesResponse{}},
+	{Method: http.MethodPost, Path: "/books", Tag: "authoring", Summary: "Publish a book", Description: "Only authors can publish books.", Auth: true, Scopes: []string{token.ScopeBooksManage}, Request: uploadBookForm{}, Form: true, Response: UploadBookResponse{}},
+	{Method: http.MethodPatch, Path: "/books", Tag: "authoring", Summary: "Update a book of the current author", Auth: true, Scopes: []string{token.ScopeBooksManage}, Request: updateBookForm{}, Form: true, Response: UploadBookResponse{}, Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/books/:id", Tag: "authoring", Summary: "Delete a book of the current author", Auth: true, Scopes: []string{token.ScopeBooksManage}, Request: BookId{}, Response: "", Errors: []int{http.StatusNotFound}},
+}
@@ -4,32 +4,40 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/metrics"
 	"github.com/zura-t/bookstore_fiber/middlewares/auth"
 	role "github.com/zura-t/bookstore_fiber/middlewares/roles"
+	"github.com/zura-t/bookstore_fiber/service"
 	"github.com/zura-t/bookstore_fiber/token"
-	"gorm.io/gorm"
 )
 
 type bookRouter struct {
-	log    *logrus.Logger
-	config config.Config
-	db     *gorm.DB
+	log      *logrus.Logger
+	config   config.Config
+	books    *service.BookService
+	readList *service.ReadListService
+	metrics  *metrics.Metrics
 }
 
-func NewBookRouter(app *fiber.App, log *logrus.Logger, config config.Config, db *gorm.DB, token *token.JwtMaker) {
-	r := &bookRouter{log, config, db}
-	app.Get("/authors", r.GetAuthors)
-	app.Get("/books", r.GetBooks)
-	app.Get("/books/:id", r.GetBook)
+func NewBookRouter(app fiber.Router, log *logrus.Logger, config config.Config, services service.Services, maker *token.JwtMaker, metrics *metrics.Metrics) {
+	r := &bookRouter{log, config, services.Books, services.ReadList, metrics}
+	// The catalog is public, API keys sent to it need the catalog scope.
+	catalog := auth.Optional(log, maker, services, token.ScopeCatalogRead)
+	app.Get("/authors", catalog, r.GetAuthors)
+	app.Get("/books", catalog, r.GetBooks)
+	app.Get("/books/:id", catalog, r.GetBook)
 
-	authRoutes := app.Group("/", auth.New(log, token))
-	authRoutes.Get("/readlist", r.GetReadList)
-	authRoutes.Post("/readlist", r.AddBookToReadList)
-	authRoutes.Delete("/readlist/:bookid", r.DeleteBookFromReadList)
-	authRoutes.Get("/books/my/list", r.GetAuthorBooks)
+	session := auth.New(log, maker, services)
+	app.Get("/readlist", session, r.GetReadList)
+	app.Post("/readlist", session, r.AddBookToReadList)
+	app.Delete("/readlist/:bookid", session, r.DeleteBookFromReadList)
 
-	bookRoutes := authRoutes.Group("/books", role.New(log, db))
-	bookRoutes.Post("/", r.UploadBook)
-	bookRoutes.Patch("/", r.UpdateBook)
-	bookRoutes.Delete("/:id", r.DeleteBook)
+	manage := auth.New(log, maker, services, token.ScopeBooksManage)
+	app.Get("/books/my/list", manage, r.GetAuthorBooks)
+	app.Get("/books/my/sales", auth.New(log, maker, services, token.ScopeSalesRead), r.GetSales)
+
+	author := role.New(log, services.Users)
+	app.Post("/books", manage, author, r.UploadBook)
+	app.Patch("/books", manage, author, r.UpdateBook)
+	app.Delete("/books/:id", manage, author, r.DeleteBook)
 }
@@ -0,0 +1,135 @@
+package book_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/apitest"
+	"github.com/zura-t/bookstore_fiber/api/book"
+	"github.com/zura-t/bookstore_fiber/api/user"
+)
+
+func TestBookRoutes(t *testing.T) {
+	h := apitest.New(t)
+	author := h.CreateAuthor()
+	reader := h.CreateUser()
+	published := h.CreateBook(author)
+	removed := h.CreateBook(author)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		token  string
+		status int
+	}{
+		{"authors", http.MethodGet, "/authors", nil, "", http.StatusOK},
+		{"books", http.MethodGet, "/books?limit=5&order_desc=true", nil, "", http.StatusOK},
+		{"books invalid offset", http.MethodGet, "/books?offset=-1", nil, "", http.StatusBadRequest},
+		{"book", http.MethodGet, fmt.Sprintf("/books/%d", published.ID), nil, "", http.StatusOK},
+		{"unknown book", http.MethodGet, "/books/9999", nil, "", http.StatusNotFound},
+		{"read list without token", http.MethodGet, "/readlist", nil, "", http.StatusUnauthorized},
+		{"add to read list", http.MethodPost, "/readlist", map[string]uint{"book_id": published.ID}, reader.Token, http.StatusOK},
+		{"add to read list twice", http.MethodPost, "/readlist", map[string]uint{"book_id": published.ID}, reader.Token, http.StatusConflict},
+		{"add unknown book to read list", http.MethodPost, "/readlist", map[string]uint{"book_id": 9999}, reader.Token, http.StatusNotFound},
+		{"add to read list invalid body", http.MethodPost, "/readlist", map[string]uint{"book_id": 0}, reader.Token, http.StatusBadRequest},
+		{"read list", http.MethodGet, "/readlist", nil, reader.Token, http.StatusOK},
+		{"remove from read list", http.MethodDelete, fmt.Sprintf("/readlist/%d", published.ID), nil, reader.Token, http.StatusOK},
+		{"author books", http.MethodGet, "/books/my/list", nil, author.Token, http.StatusOK},
+		{"author books without token", http.MethodGet, "/books/my/list", nil, "", http.StatusUnauthorized},
+		{"update as reader", http.MethodPatch, "/books", map[string]interface{}{"id": published.ID, "title": "New", "price": 5}, reader.Token, http.StatusForbidden},
+		{"update without file", http.MethodPatch, "/books", map[string]interface{}{"id": published.ID, "title": "New", "price": 5}, author.Token, http.StatusBadRequest},
+		{"delete as reader", http.MethodDelete, fmt.Sprintf("/books/%d", removed.ID), nil, reader.Token, http.StatusForbidden},
+		{"delete", http.MethodDelete, fmt.Sprintf("/books/%d", removed.ID), nil, author.Token, http.StatusOK},
+		{"deleted book", http.MethodGet, fmt.Sprintf("/books/%d", removed.ID), nil, "", http.StatusNotFound},
+		{"delete unknown book", http.MethodDelete, "/books/9999", nil, author.Token, http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := h.Request(tc.method, tc.path, tc.body, tc.token)
+			apitest.Decode(t, resp, tc.status, nil)
+		})
+	}
+}
+
+func TestUploadBook(t *testing.T) {
+	h := apitest.New(t)
+	author := h.CreateAuthor()
+	reader := h.CreateUser()
+
+	fields := map[string]string{"title": "Dune", "description": "Desert planet", "price": "10"}
+
+	req := h.Multipart(http.MethodPost, "/books", fields, "book", "dune.pdf", []byte("spice"), reader.Token)
+	apitest.Decode(t, h.Do(req), http.StatusForbidden, nil)
+
+	req = h.Multipart(http.MethodPost, "/books", fields, "", "", nil, author.Token)
+	apitest.Decode(t, h.Do(req), http.StatusBadRequest, nil)
+
+	req = h.Multipart(http.MethodPost, "/books", fields, "book", "dune.pdf", []byte("spice"), author.Token)
+	var uploaded book.UploadBookResponse
+	apitest.Decode(t, h.Do(req), http.StatusOK, &uploaded)
+	require.Equal(t, "Dune", uploaded.Title)
+	require.Equal(t, author.Id, uploaded.AuthorID)
+
+	var books []book.BookResponse
+	apitest.Decode(t, h.Request(http.MethodGet, "/books?title=Dune", nil, ""), http.StatusOK, &books)
+	require.Len(t, books, 1)
+	require.Equal(t, uploaded.ID, books[0].Id)
+	require.Equal(t, author.Name, books[0].AuthorName)
+}
+
+func TestUpdateBook(t *testing.T) {
+	h := apitest.New(t)
+	author := h.CreateAuthor()
+	other := h.CreateAuthor()
+	published := h.CreateBook(author)
+
+	fields := map[string]string{"id": fmt.Sprint(published.ID), "title": "Dune Messiah", "price": "12"}
+
+	req := h.Multipart(http.MethodPatch, "/books", fields, "book", "messiah.pdf", []byte("spice"), other.Token)
+	apitest.Decode(t, h.Do(req), http.StatusNotFound, nil)
+
+	req = h.Multipart(http.MethodPatch, "/books", fields, "book", "messiah.pdf", []byte("spice"), author.Token)
+	var updated book.UploadBookResponse
+	apitest.Decode(t, h.Do(req), http.StatusOK, &updated)
+	require.Equal(t, "Dune Messiah", updated.Title)
+	require.Equal(t, uint(12), updated.Price)
+	require.Equal(t, published.Description, updated.Description)
+}
+
+func TestSales(t *testing.T) {
+	h := apitest.New(t)
+	author := h.CreateAuthor()
+	reader := h.CreateUser()
+	wanted := h.CreateBook(author)
+	h.CreateBook(author)
+
+	apitest.Decode(t, h.Request(http.MethodPost, "/cart/", map[string]uint{"book_id": wanted.ID}, reader.Token), http.StatusOK, nil)
+	apitest.Decode(t, h.Request(http.MethodPost, "/readlist", map[string]uint{"book_id": wanted.ID}, reader.Token), http.StatusOK, nil)
+	apitest.Decode(t, h.Request(http.MethodPost, "/readlist", map[string]uint{"book_id": wanted.ID}, author.Token), http.StatusOK, nil)
+
+	createKey := func(scope string) string {
+		var created user.CreateApiKeyResponse
+		resp := h.Request(http.MethodPost, "/users/my_profile/api_keys", map[string]interface{}{"name": scope, "scopes": []string{scope}}, author.Token)
+		apitest.Decode(t, resp, http.StatusCreated, &created)
+		return created.Key
+	}
+
+	var sales []book.BookSalesResponse
+	apitest.Decode(t, h.Request(http.MethodGet, "/books/my/sales", nil, createKey("sales:read")), http.StatusOK, &sales)
+	require.Len(t, sales, 2)
+	counts := map[uint][2]int{}
+	for _, s := range sales {
+		counts[s.Id] = [2]int{s.InCarts, s.InReadLists}
+	}
+	require.Equal(t, [2]int{1, 2}, counts[wanted.ID])
+
+	apitest.Decode(t, h.Request(http.MethodGet, "/books/my/sales", nil, createKey("catalog:read")), http.StatusForbidden, nil)
+
+	apitest.Decode(t, h.Request(http.MethodGet, "/books/my/sales", nil, reader.Token), http.StatusOK, &sales)
+	require.Empty(t, sales)
+}
@@ -0,0 +1,27 @@
+package book
+
+import (
+	"mime/multipart"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ErrFileRequired is returned when an upload has no "book" file part.
+var ErrFileRequired = apperror.New(apperror.Invalid, "file_required", "You didn't attach the file")
+
+// saveUpload stores an uploaded book file at path.
+func (r *bookRouter) saveUpload(c *fiber.Ctx, file *multipart.FileHeader, path string) (err error) {
+	_, span := tracing.Start(c.UserContext(), "storage.save",
+		attribute.String("file.path", path),
+		attribute.Int64("file.size", file.Size))
+	defer func() { tracing.End(span, err) }()
+
+	if err := c.SaveFile(file, path); err != nil {
+		return err
+	}
+	r.metrics.Upload("book", file.Size)
+	return nil
+}
@@ -1,15 +1,12 @@
 package book
 
 import (
-	"fmt"
 	"mime/multipart"
 	"time"
 
-	// "github.com/go-playground/validator/v10"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
 	"github.com/zura-t/bookstore_fiber/pkg"
 	"github.com/zura-t/bookstore_fiber/token"
 )
@@ -18,81 +15,45 @@ func (r *bookRouter) UploadBook(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
 	var book = &UploadBook{}
-	if err := c.BodyParser(book); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-	validate := validator.New()
-	if err := validate.Struct(book); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(book, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+	if err := pkg.Bind(c, book); err != nil {
+		return err
 	}
 
 	file, err := c.FormFile("book")
 	if err != nil {
-		err := fmt.Errorf("Can't get file")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return ErrFileRequired.Wrap(err)
 	}
 	if file == nil {
-		err := fmt.Errorf("You didn't attach the file")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return ErrFileRequired
 	}
 
 	path := "public/uploads/" + file.Filename
 
-	c.SaveFile(file, path)
+	if err := r.saveUpload(c, file, path); err != nil {
+		return err
+	}
 
-	arg := models.Book{
+	res, err := r.books.Publish(c.UserContext(), data.UserId, entity.Book{
 		Title:       book.Title,
 		Description: book.Description,
-		AuthorID:    data.UserId,
 		Price:       book.Price,
 		File:        path,
-	}
-
-	err = r.db.Create(&arg).Error
+	})
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
-	res := convertBook(arg)
-
-	return c.JSON(res)
+	return c.JSON(convertBook(*res))
 }
 
 type UploadBook struct {
 	Title       string                `form:"title" validate:"required,min=1"`
 	Description string                `form:"description" validate:"required,min=1"`
-	Price       uint                  `form:"price" validate:"required,min=1"`
+	Price       uint                  `form:"price" validate:"required,price"`
 	Book        *multipart.FileHeader `form:"book"`
 }
 
@@ -107,13 +68,13 @@ type UploadBookResponse struct {
 	File        string    `json:"file"`
 }
 
-func convertBook(book models.Book) *UploadBookResponse {
+func convertBook(book entity.Book) *UploadBookResponse {
 	return &UploadBookResponse{
-		ID:          book.ID,
+		ID:          book.Id,
 		Title:       book.Title,
 		Description: book.Description,
 		Price:       book.Price,
-		AuthorID:    book.AuthorID,
+		AuthorID:    book.Author.Id,
 		File:        book.File,
 		CreatedAt:   book.CreatedAt,
 		UpdatedAt:   book.UpdatedAt,
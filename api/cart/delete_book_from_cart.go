@@ -1,81 +1,32 @@
 package cart
 
 import (
-	"errors"
-	"fmt"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/pkg"
 	"github.com/zura-t/bookstore_fiber/token"
-	"gorm.io/gorm"
 )
 
 func (r cartRouter) DeleteBookFromCart(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
 	var req = &DeleteBookFromCart{}
-	if err := c.ParamsParser(req); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+	if err := pkg.Bind(c, req); err != nil {
+		return err
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(req); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(req, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-
-	var book models.Book
-
-	err := r.db.First(&book, req.Id).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			err := fmt.Errorf("Book not found")
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(err)
-			return c.Status(fiber.StatusNotFound).JSON(pkg.ErrorResponse(err))
-		}
-
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
-	}
-
-	err = r.db.Delete(&models.CartItem{}, &models.CartItem{BookID: book.ID, UserID: data.UserId}).Error
+	err := r.cart.Remove(c.UserContext(), data.UserId, req.Id)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		})
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	return c.SendString("Book has deleted")
 }
 
 type DeleteBookFromCart struct {
-	Id uint `uri:"book_id" json:"book_id" validate:"required,min=1"`
+	Id uint `params:"id" validate:"required,min=1"`
 }
@@ -1,15 +1,11 @@
 package cart
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
-	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/token"
-	"gorm.io/gorm"
 )
 
 type CartItemResponse struct {
@@ -37,22 +33,12 @@ func (r cartRouter) GetBooksInCart(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
-	var cartItems []models.CartItem
-	err := r.db.Preload("Book", func(tx *gorm.DB) *gorm.DB {
-		return tx.Preload("Author")
-	}).Order("cart_items.created_at DESC").Limit(limit).Offset(offset).Find(&cartItems, &models.CartItem{UserID: data.UserId}).Error
+	cartItems, err := r.cart.List(c.UserContext(), data.UserId, limit, offset)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	res := make([]*CartItemResponse, len(cartItems))
@@ -0,0 +1,15 @@
+package cart
+
+import (
+	"net/http"
+
+	"github.com/zura-t/bookstore_fiber/api/openapi"
+)
+
+// Routes documents the routes registered by NewCartRouter.
+var Routes = []openapi.Route{
+	{Method: http.MethodPost, Path: "/cart", Tag: "cart", Summary: "Add a book to the cart", Auth: true, Request: AddBookToCart{}, Response: CartItemResponse{}, Errors: []int{http.StatusNotFound, http.StatusConflict}},
+	{Method: http.MethodGet, Path: "/cart", Tag: "cart", Summary: "List the books in the cart", Auth: true, Parameters: openapi.PageParameters, Response: []CartItemResponse{}},
+	{Method: http.MethodDelete, Path: "/cart/:id", Tag: "cart", Summary: "Remove a book from the cart", Auth: true, Request: DeleteBookFromCart{}, Response: "", Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/cart", Tag: "cart", Summary: "Empty the cart", Auth: true, Response: ""},
+}
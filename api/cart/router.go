@@ -5,21 +5,19 @@ import (
 	"github.com/sirupsen/logrus"
 	"github.com/zura-t/bookstore_fiber/config"
 	"github.com/zura-t/bookstore_fiber/middlewares/auth"
+	"github.com/zura-t/bookstore_fiber/service"
 	"github.com/zura-t/bookstore_fiber/token"
-	"gorm.io/gorm"
 )
 
 type cartRouter struct {
 	log    *logrus.Logger
 	config config.Config
-	db     *gorm.DB
+	cart   *service.CartService
 }
 
-func NewCartRouter(app *fiber.App, log *logrus.Logger, config config.Config, db *gorm.DB, token *token.JwtMaker) {
-	r := &cartRouter{log, config, db}
-	authRoutes := app.Group("/", auth.New(log, token))
-
-	cartRoutes := authRoutes.Group("/cart")
+func NewCartRouter(app fiber.Router, log *logrus.Logger, config config.Config, services service.Services, token *token.JwtMaker) {
+	r := &cartRouter{log, config, services.Cart}
+	cartRoutes := app.Group("/cart", auth.New(log, token, services))
 	cartRoutes.Post("/", r.AddBookToCart)
 	cartRoutes.Get("/", r.GetBooksInCart)
 	cartRoutes.Delete("/:id", r.DeleteBookFromCart)
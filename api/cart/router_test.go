@@ -0,0 +1,70 @@
+package cart_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/apitest"
+	"github.com/zura-t/bookstore_fiber/api/cart"
+)
+
+func TestCartRoutes(t *testing.T) {
+	h := apitest.New(t)
+	author := h.CreateAuthor()
+	user := h.CreateUser()
+	book := h.CreateBook(author)
+	other := h.CreateBook(author)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		token  string
+		status int
+	}{
+		{"add without token", http.MethodPost, "/cart/", map[string]uint{"book_id": book.ID}, "", http.StatusUnauthorized},
+		{"add invalid body", http.MethodPost, "/cart/", map[string]uint{"book_id": 0}, user.Token, http.StatusBadRequest},
+		{"add unknown book", http.MethodPost, "/cart/", map[string]uint{"book_id": 9999}, user.Token, http.StatusNotFound},
+		{"add", http.MethodPost, "/cart/", map[string]uint{"book_id": book.ID}, user.Token, http.StatusOK},
+		{"add other", http.MethodPost, "/cart/", map[string]uint{"book_id": other.ID}, user.Token, http.StatusOK},
+		{"add twice", http.MethodPost, "/cart/", map[string]uint{"book_id": book.ID}, user.Token, http.StatusConflict},
+		{"list without token", http.MethodGet, "/cart/", nil, "", http.StatusUnauthorized},
+		{"list", http.MethodGet, "/cart/", nil, user.Token, http.StatusOK},
+		{"remove unknown book", http.MethodDelete, "/cart/9999", nil, user.Token, http.StatusNotFound},
+		{"remove", http.MethodDelete, fmt.Sprintf("/cart/%d", book.ID), nil, user.Token, http.StatusOK},
+		{"clear without token", http.MethodDelete, "/cart/", nil, "", http.StatusUnauthorized},
+		{"clear", http.MethodDelete, "/cart/", nil, user.Token, http.StatusOK},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := h.Request(tc.method, tc.path, tc.body, tc.token)
+			apitest.Decode(t, resp, tc.status, nil)
+		})
+	}
+}
+
+func TestCartContents(t *testing.T) {
+	h := apitest.New(t)
+	author := h.CreateAuthor()
+	user := h.CreateUser()
+	book := h.CreateBook(author)
+
+	resp := h.Request(http.MethodPost, "/cart/", map[string]uint{"book_id": book.ID}, user.Token)
+	var item cart.CartItemResponse
+	apitest.Decode(t, resp, http.StatusOK, &item)
+	require.Equal(t, book.ID, item.BookID)
+	require.Equal(t, author.Name, item.Book.AuthorName)
+
+	var items []cart.CartItemResponse
+	apitest.Decode(t, h.Request(http.MethodGet, "/cart/", nil, user.Token), http.StatusOK, &items)
+	require.Len(t, items, 1)
+	require.Equal(t, book.Title, items[0].Book.Title)
+
+	apitest.Decode(t, h.Request(http.MethodDelete, "/cart/", nil, user.Token), http.StatusOK, nil)
+	apitest.Decode(t, h.Request(http.MethodGet, "/cart/", nil, user.Token), http.StatusOK, &items)
+	require.Empty(t, items)
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/api/health"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/database"
+	"github.com/zura-t/bookstore_fiber/jobs"
+	"github.com/zura-t/bookstore_fiber/migrations"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+// Dependencies are built once and shared by the HTTP and gRPC servers.
+type Dependencies struct {
+	Services service.Services
+	Maker    *token.JwtMaker
+	// Checks decide whether the process is ready to serve requests.
+	Checks []health.Check
+}
+
+func NewDependencies(log *logrus.Logger, config config.Config, cluster *database.Cluster, repos repository.Repositories, exporter *jobs.DataExporter) (Dependencies, error) {
+	maker, err := token.NewJwtMaker(log, config.TokenKey)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	hasher := pkg.NewPasswordHasher(pkg.Argon2Params{
+		Memory:      config.Argon2Memory,
+		Iterations:  config.Argon2Iterations,
+		Parallelism: config.Argon2Parallelism,
+	})
+
+	policy := pkg.NewPasswordPolicy(config.PasswordMinLength, config.PasswordMaxLength)
+	if config.BreachedPasswordsFile != "" {
+		if err := policy.LoadBreachedPasswords(config.BreachedPasswordsFile); err != nil {
+			return Dependencies{}, err
+		}
+	}
+
+	migrator, err := migrations.New(cluster.Primary)
+	if err != nil {
+		return Dependencies{}, err
+	}
+
+	return Dependencies{
+		Services: service.New(log, config, repos, hasher, policy, exporter),
+		Maker:    maker,
+		Checks: []health.Check{
+			health.DatabaseCheck(cluster),
+			health.StorageCheck("public/uploads", config.ExportDir),
+			health.MigrationsCheck(migrator),
+		},
+	}, nil
+}
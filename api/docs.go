@@ -0,0 +1,58 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/api/book"
+	"github.com/zura-t/bookstore_fiber/api/cart"
+	"github.com/zura-t/bookstore_fiber/api/openapi"
+	"github.com/zura-t/bookstore_fiber/api/user"
+)
+
+// docsPage renders /openapi.json with Swagger UI.
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Bookstore API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+  </script>
+</body>
+</html>
+`
+
+// OpenAPI documents version 1 of the API, the routes of the user, book and
+// cart routers.
+func OpenAPI() (*openapi.Document, error) {
+	doc, err := openapi.New(openapi.Info{
+		Title:       "Bookstore API",
+		Description: "Errors are RFC 7807 problem details, match on their code. The same routes without the /v1 prefix are deprecated.",
+		Version:     "1.0.0",
+	}, user.Routes, book.Routes, cart.Routes)
+	if err != nil {
+		return nil, err
+	}
+	doc.Servers = []openapi.Server{{URL: "/v1"}}
+	return doc, nil
+}
+
+func newDocsRouter(app *fiber.App) error {
+	doc, err := OpenAPI()
+	if err != nil {
+		return err
+	}
+
+	app.Get("/openapi.json", func(c *fiber.Ctx) error {
+		return c.JSON(doc)
+	})
+	app.Get("/docs", func(c *fiber.Ctx) error {
+		c.Type("html")
+		return c.SendString(docsPage)
+	})
+	return nil
+}
@@ -0,0 +1,97 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/openapi"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/metrics"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+// TestOpenAPIMatchesRoutes fails when a route is registered without being
+// documented or the other way around.
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	maker, err := token.NewJwtMaker(log, pkg.RandomString(32))
+	require.NoError(t, err)
+	services := service.New(log, config.Config{}, repository.NewMemoryRepositories(), nil, nil, nil)
+
+	app := fiber.New()
+	versionRoutes{log: log, services: services, maker: maker, metrics: metrics.New()}.mountV1(app.Group("/v1"))
+
+	seen := make(map[string]bool)
+	var registered []string
+	for _, route := range app.GetRoutes(true) {
+		path := strings.TrimSuffix(strings.TrimPrefix(route.Path, "/v1"), "/")
+		op := route.Method + " " + openapi.Path(path)
+		if route.Method == http.MethodHead || seen[op] {
+			continue
+		}
+		seen[op] = true
+		registered = append(registered, op)
+	}
+	sort.Strings(registered)
+
+	doc, err := OpenAPI()
+	require.NoError(t, err)
+	require.Equal(t, registered, doc.Operations())
+
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			for _, param := range op.Parameters {
+				if param.In == "path" {
+					require.Contains(t, path, "{"+param.Name+"}", "%s %s", method, path)
+				}
+			}
+		}
+	}
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	app := fiber.New()
+	require.NoError(t, newDocsRouter(app))
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var doc openapi.Document
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&doc))
+	require.Equal(t, openapi.Version, doc.OpenAPI)
+	require.Equal(t, []openapi.Server{{URL: "/v1"}}, doc.Servers)
+
+	register := doc.Paths["/register"]["post"]
+	schema := register.RequestBody.Content["application/json"].Schema
+	require.ElementsMatch(t, []string{"name", "email", "password"}, schema.Required)
+	require.Equal(t, "email", schema.Properties["email"].Format)
+
+	upload := doc.Paths["/books"]["post"]
+	form := upload.RequestBody.Content["multipart/form-data"].Schema
+	require.Equal(t, "binary", form.Properties["book"].Format)
+	require.Equal(t, float64(pkg.MaxPrice), *form.Properties["price"].Maximum)
+	require.Len(t, upload.Security, 2)
+
+	profile := doc.Paths["/users/my_profile"]["get"]
+	require.Equal(t, []map[string][]string{{openapi.BearerAuth: {}}}, profile.Security)
+	require.Equal(t, "#/components/schemas/UserResponse", profile.Responses["200"].Content["application/json"].Schema.Ref)
+	require.Contains(t, doc.Components.Schemas, "UserResponse")
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/docs", nil))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, resp.Header.Get(fiber.HeaderContentType), "text/html")
+}
@@ -0,0 +1,99 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/zura-t/bookstore_fiber/apperror"
+)
+
+var (
+	ErrQueryTooDeep    = apperror.New(apperror.Invalid, "query_too_deep", "Query is nested too deeply")
+	ErrQueryTooComplex = apperror.New(apperror.Invalid, "query_too_complex", "Query is too complex")
+)
+
+// defaultListSize is the cost multiplier of list fields without a limit
+// argument.
+const defaultListSize = 20
+
+// checkLimits rejects operations nested deeper than maxDepth or costing more
+// than maxComplexity. Every field costs 1 plus the cost of its selections,
+// which list fields pay once per item they can return. Introspection is
+// free, so that tools can always fetch the schema.
+func checkLimits(op *ast.OperationDefinition, vars map[string]interface{}, maxDepth, maxComplexity int) error {
+	if depth := depth(op.SelectionSet); depth > maxDepth {
+		return ErrQueryTooDeep.
+			WithMessage(fmt.Sprintf("Query depth %d exceeds the limit of %d", depth, maxDepth)).
+			WithParams(strconv.Itoa(depth), strconv.Itoa(maxDepth))
+	}
+	if cost := complexity(op.SelectionSet, vars); cost > maxComplexity {
+		return ErrQueryTooComplex.
+			WithMessage(fmt.Sprintf("Query complexity %d exceeds the limit of %d", cost, maxComplexity)).
+			WithParams(strconv.Itoa(cost), strconv.Itoa(maxComplexity))
+	}
+	return nil
+}
+
+func depth(set ast.SelectionSet) int {
+	max := 0
+	for _, sel := range set {
+		d := 0
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if introspection(sel) {
+				continue
+			}
+			d = 1 + depth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			d = depth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			d = depth(sel.Definition.SelectionSet)
+		}
+		if d > max {
+			max = d
+		}
+	}
+	return max
+}
+
+func complexity(set ast.SelectionSet, vars map[string]interface{}) int {
+	total := 0
+	for _, sel := range set {
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if introspection(sel) {
+				continue
+			}
+			cost := complexity(sel.SelectionSet, vars)
+			if sel.Definition.Type.Elem != nil {
+				cost *= listSize(sel, vars)
+			}
+			total += 1 + cost
+		case *ast.InlineFragment:
+			total += complexity(sel.SelectionSet, vars)
+		case *ast.FragmentSpread:
+			total += complexity(sel.Definition.SelectionSet, vars)
+		}
+	}
+	return total
+}
+
+func listSize(field *ast.Field, vars map[string]interface{}) int {
+	switch limit := field.ArgumentMap(vars)["limit"].(type) {
+	case int64:
+		if limit >= 0 {
+			return int(limit)
+		}
+	case int:
+		if limit >= 0 {
+			return limit
+		}
+	}
+	return defaultListSize
+}
+
+func introspection(field *ast.Field) bool {
+	return strings.HasPrefix(field.Name, "__")
+}
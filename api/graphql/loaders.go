@@ -0,0 +1,72 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/service"
+)
+
+// loaders batch the lookups of one request, so that resolving a field of
+// every book in a list takes one query instead of one per book. They cache
+// for the lifetime of the request only.
+type loaders struct {
+	authors    *dataloader.Loader[uint, *entity.Author]
+	inCart     *dataloader.Loader[uint, bool]
+	inReadList *dataloader.Loader[uint, bool]
+}
+
+// newLoaders returns loaders for the user with userId, 0 for anonymous
+// requests, which have no cart or read list to load.
+func newLoaders(services service.Services, userId uint) *loaders {
+	l := &loaders{
+		authors: dataloader.NewBatchedLoader(loadAuthors(services.Books)),
+	}
+	if userId != 0 {
+		l.inCart = dataloader.NewBatchedLoader(loadContains(services.Cart.Contains, userId))
+		l.inReadList = dataloader.NewBatchedLoader(loadContains(services.ReadList.Contains, userId))
+	}
+	return l
+}
+
+// loadAuthors loads nil for authors that don't exist anymore.
+func loadAuthors(books *service.BookService) dataloader.BatchFunc[uint, *entity.Author] {
+	return func(ctx context.Context, ids []uint) []*dataloader.Result[*entity.Author] {
+		authors, err := books.ListAuthors(ctx, repository.AuthorFilter{IDs: ids, Limit: len(ids)})
+		byId := make(map[uint]*entity.Author, len(authors))
+		for i := range authors {
+			byId[authors[i].Id] = &authors[i]
+		}
+
+		results := make([]*dataloader.Result[*entity.Author], len(ids))
+		for i, id := range ids {
+			results[i] = &dataloader.Result[*entity.Author]{Data: byId[id], Error: err}
+		}
+		return results
+	}
+}
+
+func loadContains(contains func(ctx context.Context, userId uint, bookIds []uint) (map[uint]bool, error), userId uint) dataloader.BatchFunc[uint, bool] {
+	return func(ctx context.Context, bookIds []uint) []*dataloader.Result[bool] {
+		found, err := contains(ctx, userId, bookIds)
+		results := make([]*dataloader.Result[bool], len(bookIds))
+		for i, id := range bookIds {
+			results[i] = &dataloader.Result[bool]{Data: found[id], Error: err}
+		}
+		return results
+	}
+}
+
+// load returns the value of key. graphql-go resolves the items of a list
+// with limited parallelism, so the first item to load queues the keys of
+// all of them, which are then loaded in one batch.
+func load[V any](ctx context.Context, loader *dataloader.Loader[uint, V], siblings []uint, key uint) (V, error) {
+	for _, sibling := range siblings {
+		// Load doesn't block and queues a key only once, later calls hit
+		// the cache.
+		loader.Load(ctx, sibling)
+	}
+	return loader.Load(ctx, key)()
+}
@@ -0,0 +1,346 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/middlewares/auth"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+// viewer is the client of a request, stored in its context by the handler.
+type viewer struct {
+	// user is nil for anonymous requests.
+	user    *token.Payload
+	apiKey  bool
+	loaders *loaders
+}
+
+type viewerKey struct{}
+
+func withViewer(ctx context.Context, v *viewer) context.Context {
+	return context.WithValue(ctx, viewerKey{}, v)
+}
+
+func viewerOf(ctx context.Context) *viewer {
+	return ctx.Value(viewerKey{}).(*viewer)
+}
+
+// session returns the user of a JWT session, nil for anonymous requests.
+// Like the cart and profile routes, the user's own data isn't available to
+// API keys.
+func session(ctx context.Context) (*token.Payload, error) {
+	v := viewerOf(ctx)
+	if v.apiKey {
+		return nil, auth.ErrApiKeyNotAllowed
+	}
+	return v.user, nil
+}
+
+type resolver struct {
+	services service.Services
+}
+
+// Arguments with defaults are never null, graphql-go fills them in.
+type pageArgs struct {
+	Limit  int32
+	Offset int32
+}
+
+func page(limit, offset int32) (int, int, error) {
+	if limit < 0 || offset < 0 {
+		return 0, 0, apperror.ErrInvalidParams
+	}
+	return int(limit), int(offset), nil
+}
+
+func (r *resolver) Book(ctx context.Context, args struct{ ID graphql.ID }) (*bookResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	book, err := r.services.Books.Get(ctx, id)
+	if errors.Is(err, service.ErrBookNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newBookResolvers([]entity.Book{*book})[0], nil
+}
+
+func (r *resolver) Books(ctx context.Context, args struct {
+	Title     *string
+	AuthorID  *graphql.ID
+	Limit     int32
+	Offset    int32
+	OrderDesc bool
+}) ([]*bookResolver, error) {
+	limit, offset, err := page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.BookFilter{Limit: limit, Offset: offset, OrderDesc: args.OrderDesc}
+	if args.Title != nil {
+		filter.Title = *args.Title
+	}
+	if args.AuthorID != nil {
+		if filter.AuthorID, err = parseID(*args.AuthorID); err != nil {
+			return nil, err
+		}
+	}
+
+	books, err := r.services.Books.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	return newBookResolvers(books), nil
+}
+
+func (r *resolver) Authors(ctx context.Context, args struct {
+	Name      *string
+	Limit     int32
+	Offset    int32
+	OrderDesc bool
+}) ([]*authorResolver, error) {
+	limit, offset, err := page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+	filter := repository.AuthorFilter{Limit: limit, Offset: offset, OrderDesc: args.OrderDesc}
+	if args.Name != nil {
+		filter.Name = *args.Name
+	}
+
+	authors, err := r.services.Books.ListAuthors(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*authorResolver, len(authors))
+	for i := range authors {
+		res[i] = &authorResolver{authors[i]}
+	}
+	return res, nil
+}
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	payload, err := session(ctx)
+	if err != nil || payload == nil {
+		return nil, err
+	}
+
+	user, err := r.services.Users.Get(ctx, payload.UserId)
+	if err != nil {
+		return nil, err
+	}
+	return &userResolver{r.services, *user}, nil
+}
+
+type bookResolver struct {
+	book entity.Book
+	// books and authors are the ids of the books of the list the book was
+	// resolved in and of their authors, see load.
+	books   []uint
+	authors []uint
+}
+
+func newBookResolvers(books []entity.Book) []*bookResolver {
+	bookIds := make([]uint, len(books))
+	authorIds := make([]uint, len(books))
+	for i, book := range books {
+		bookIds[i] = book.Id
+		authorIds[i] = book.Author.Id
+	}
+
+	res := make([]*bookResolver, len(books))
+	for i, book := range books {
+		res[i] = &bookResolver{book, bookIds, authorIds}
+	}
+	return res
+}
+
+func (r *bookResolver) ID() graphql.ID {
+	return formatID(r.book.Id)
+}
+
+func (r *bookResolver) Title() string {
+	return r.book.Title
+}
+
+func (r *bookResolver) Description() string {
+	return r.book.Description
+}
+
+func (r *bookResolver) Price() int32 {
+	return int32(r.book.Price)
+}
+
+func (r *bookResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.book.CreatedAt}
+}
+
+func (r *bookResolver) UpdatedAt() graphql.Time {
+	return graphql.Time{Time: r.book.UpdatedAt}
+}
+
+func (r *bookResolver) Author(ctx context.Context) (*authorResolver, error) {
+	author, err := load(ctx, viewerOf(ctx).loaders.authors, r.authors, r.book.Author.Id)
+	if err != nil || author == nil {
+		return nil, err
+	}
+	return &authorResolver{*author}, nil
+}
+
+func (r *bookResolver) InMyCart(ctx context.Context) (bool, error) {
+	if user, err := session(ctx); err != nil || user == nil {
+		return false, err
+	}
+	return load(ctx, viewerOf(ctx).loaders.inCart, r.books, r.book.Id)
+}
+
+func (r *bookResolver) InMyReadList(ctx context.Context) (bool, error) {
+	if user, err := session(ctx); err != nil || user == nil {
+		return false, err
+	}
+	return load(ctx, viewerOf(ctx).loaders.inReadList, r.books, r.book.Id)
+}
+
+type authorResolver struct {
+	author entity.Author
+}
+
+func (r *authorResolver) ID() graphql.ID {
+	return formatID(r.author.Id)
+}
+
+func (r *authorResolver) Name() string {
+	return r.author.Name
+}
+
+func (r *authorResolver) BooksCount() int32 {
+	return int32(r.author.BooksCount)
+}
+
+type userResolver struct {
+	services service.Services
+	user     entity.User
+}
+
+func (r *userResolver) ID() graphql.ID {
+	return formatID(r.user.Id)
+}
+
+func (r *userResolver) Name() string {
+	return r.user.Name
+}
+
+func (r *userResolver) Email() string {
+	return r.user.Email
+}
+
+func (r *userResolver) IsAuthor() bool {
+	return r.user.IsAuthor
+}
+
+func (r *userResolver) Locale() string {
+	return r.user.Locale
+}
+
+func (r *userResolver) CreatedAt() graphql.Time {
+	return graphql.Time{Time: r.user.CreatedAt}
+}
+
+func (r *userResolver) Cart(ctx context.Context, args pageArgs) ([]*cartItemResolver, error) {
+	limit, offset, err := page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := r.services.Cart.List(ctx, r.user.Id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	books := make([]entity.Book, len(items))
+	for i, item := range items {
+		books[i] = item.Book
+	}
+	bookResolvers := newBookResolvers(books)
+
+	res := make([]*cartItemResolver, len(items))
+	for i, item := range items {
+		res[i] = &cartItemResolver{item, bookResolvers[i]}
+	}
+	return res, nil
+}
+
+func (r *userResolver) ReadList(ctx context.Context, args pageArgs) ([]*readListEntryResolver, error) {
+	limit, offset, err := page(args.Limit, args.Offset)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.services.ReadList.List(ctx, r.user.Id, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	books := make([]entity.Book, len(entries))
+	for i, entry := range entries {
+		books[i] = entry.Book
+	}
+	bookResolvers := newBookResolvers(books)
+
+	res := make([]*readListEntryResolver, len(entries))
+	for i, entry := range entries {
+		res[i] = &readListEntryResolver{entry, bookResolvers[i]}
+	}
+	return res, nil
+}
+
+type cartItemResolver struct {
+	item entity.CartItem
+	book *bookResolver
+}
+
+func (r *cartItemResolver) ID() graphql.ID {
+	return formatID(r.item.Id)
+}
+
+func (r *cartItemResolver) Book() *bookResolver {
+	return r.book
+}
+
+func (r *cartItemResolver) AddedAt() graphql.Time {
+	return graphql.Time{Time: r.item.CreatedAt}
+}
+
+type readListEntryResolver struct {
+	entry entity.ReadListEntry
+	book  *bookResolver
+}
+
+func (r *readListEntryResolver) Book() *bookResolver {
+	return r.book
+}
+
+func (r *readListEntryResolver) AddedAt() graphql.Time {
+	return graphql.Time{Time: r.entry.AddedAt}
+}
+
+func formatID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func parseID(id graphql.ID) (uint, error) {
+	n, err := strconv.ParseUint(string(id), 10, 0)
+	if err != nil || n == 0 {
+		return 0, apperror.ErrInvalidParams.Wrap(err)
+	}
+	return uint(n), nil
+}
@@ -0,0 +1,183 @@
+// Package graphql serves the catalog and the library of the authenticated
+// user over GraphQL, so that a client can fetch a book page in one round
+// trip. Resolvers call the same services as the REST routes.
+package graphql
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/graph-gophers/graphql-go"
+	qerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"github.com/vektah/gqlparser/v2/validator"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/i18n"
+	"github.com/zura-t/bookstore_fiber/middlewares/auth"
+	"github.com/zura-t/bookstore_fiber/middlewares/logger"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+//go:embed schema.graphql
+var Schema string
+
+// Request is the body of a POST request. GET requests pass the same fields
+// in the query string, variables encoded as JSON.
+type Request struct {
+	Query         string                 `json:"query" validate:"required"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+type getRequest struct {
+	Query         string `query:"query" validate:"required"`
+	OperationName string `query:"operationName"`
+	Variables     string `query:"variables"`
+}
+
+type graphqlRouter struct {
+	log      *logrus.Logger
+	config   config.Config
+	services service.Services
+	// schema executes queries, ast is the same schema for the limit checks,
+	// which graphql-go can't do on its own.
+	schema *graphql.Schema
+	ast    *ast.Schema
+}
+
+func NewGraphQLRouter(app fiber.Router, log *logrus.Logger, config config.Config, services service.Services, maker *token.JwtMaker) error {
+	schema, err := graphql.ParseSchema(Schema, &resolver{services}, graphql.UseStringDescriptions())
+	if err != nil {
+		return err
+	}
+	astSchema, err := gqlparser.LoadSchema(&ast.Source{Name: "schema.graphql", Input: Schema})
+	if err != nil {
+		return err
+	}
+
+	r := &graphqlRouter{log, config, services, schema, astSchema}
+	// Anonymous clients can browse the catalog, API keys need the catalog
+	// scope like the REST catalog routes.
+	authenticate := auth.Optional(log, maker, services, token.ScopeCatalogRead)
+	app.Get("/graphql", authenticate, r.GetQuery)
+	app.Post("/graphql", authenticate, r.PostQuery)
+	return nil
+}
+
+func (r *graphqlRouter) GetQuery(c *fiber.Ctx) error {
+	req := &getRequest{}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
+	}
+
+	var variables map[string]interface{}
+	if req.Variables != "" {
+		if err := json.Unmarshal([]byte(req.Variables), &variables); err != nil {
+			return apperror.ErrInvalidParams.Wrap(err)
+		}
+	}
+	return r.serve(c, Request{req.Query, req.OperationName, variables})
+}
+
+func (r *graphqlRouter) PostQuery(c *fiber.Ctx) error {
+	req := &Request{}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
+	}
+	return r.serve(c, *req)
+}
+
+// serve answers with 200 and the errors in the body once the request could
+// be read, as GraphQL clients expect.
+func (r *graphqlRouter) serve(c *fiber.Ctx, req Request) error {
+	if errs := r.check(req); len(errs) > 0 {
+		return c.JSON(graphql.Response{Errors: r.present(c, errs)})
+	}
+
+	user, _ := c.Locals("user").(*token.Payload)
+	v := &viewer{user: user, apiKey: c.Locals("api_key") != nil}
+	userId := uint(0)
+	if user != nil && !v.apiKey {
+		userId = user.UserId
+	}
+	v.loaders = newLoaders(r.services, userId)
+
+	res := r.schema.Exec(withViewer(c.UserContext(), v), req.Query, req.OperationName, req.Variables)
+	res.Errors = r.present(c, res.Errors)
+	return c.JSON(res)
+}
+
+// check validates the query and applies the depth and complexity limits
+// before anything is resolved.
+func (r *graphqlRouter) check(req Request) []*qerrors.QueryError {
+	doc, errs := gqlparser.LoadQuery(r.ast, req.Query)
+	if len(errs) > 0 {
+		return queryErrors(errs)
+	}
+	op := doc.Operations.ForName(req.OperationName)
+	if op == nil {
+		if req.OperationName == "" {
+			return []*qerrors.QueryError{{Message: "operationName is required for a query with several operations"}}
+		}
+		return []*qerrors.QueryError{{Message: "Unknown operation " + req.OperationName}}
+	}
+	vars, err := validator.VariableValues(r.ast, op, req.Variables)
+	if err != nil {
+		var gqlErr *gqlerror.Error
+		if errors.As(err, &gqlErr) {
+			return queryErrors(gqlerror.List{gqlErr})
+		}
+		return []*qerrors.QueryError{{Message: err.Error()}}
+	}
+	if err := checkLimits(op, vars, r.config.GraphQLMaxDepth, r.config.GraphQLMaxComplexity); err != nil {
+		return []*qerrors.QueryError{{Message: err.Error(), ResolverError: err}}
+	}
+	return nil
+}
+
+func queryErrors(errs gqlerror.List) []*qerrors.QueryError {
+	res := make([]*qerrors.QueryError, len(errs))
+	for i, err := range errs {
+		res[i] = &qerrors.QueryError{Message: err.Message, Extensions: err.Extensions}
+		for _, loc := range err.Locations {
+			res[i].Locations = append(res[i].Locations, qerrors.Location{Line: loc.Line, Column: loc.Column})
+		}
+	}
+	return res
+}
+
+// present translates application errors like problem.ErrorHandler does and
+// adds their code as an extension. Other resolver errors are internal, they
+// are logged and replaced.
+func (r *graphqlRouter) present(c *fiber.Ctx, errs []*qerrors.QueryError) []*qerrors.QueryError {
+	for _, err := range errs {
+		if err.ResolverError == nil {
+			continue
+		}
+
+		var appErr *apperror.Error
+		if !errors.As(err.ResolverError, &appErr) {
+			appErr = apperror.ErrInternal
+		}
+		if appErr.Kind == apperror.Internal {
+			logger.Entry(c).WithField("code", appErr.Code).Error(err.ResolverError)
+		}
+
+		locale := i18n.Locale(c)
+		c.Set(fiber.HeaderContentLanguage, locale)
+		err.Message = appErr.Message
+		if text, ok := i18n.T(locale, "errors."+appErr.Code, appErr.Params...); ok {
+			err.Message = text
+		}
+		err.Extensions = map[string]interface{}{"code": appErr.Code}
+	}
+	return errs
+}
@@ -0,0 +1,131 @@
+package graphql_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/apitest"
+	"github.com/zura-t/bookstore_fiber/config"
+)
+
+type response struct {
+	Data   map[string]interface{} `json:"data"`
+	Errors []struct {
+		Message    string            `json:"message"`
+		Extensions map[string]string `json:"extensions"`
+	} `json:"errors"`
+}
+
+func query(t *testing.T, h *apitest.Harness, query string, variables map[string]interface{}, token string) response {
+	t.Helper()
+
+	resp := h.Request(http.MethodPost, "/graphql", map[string]interface{}{"query": query, "variables": variables}, token)
+	var res response
+	apitest.Decode(t, resp, http.StatusOK, &res)
+	return res
+}
+
+func TestGraphQL(t *testing.T) {
+	h := apitest.New(t)
+	author := h.CreateAuthor()
+	user := h.CreateUser()
+	book := h.CreateBook(author)
+	other := h.CreateBook(author)
+
+	resp := h.Request(http.MethodPost, "/cart/", map[string]uint{"book_id": book.ID}, user.Token)
+	apitest.Decode(t, resp, http.StatusOK, nil)
+	resp = h.Request(http.MethodPost, "/readlist", map[string]uint{"book_id": other.ID}, user.Token)
+	apitest.Decode(t, resp, http.StatusOK, nil)
+
+	const books = `query($authorId: ID) {
+		books(authorId: $authorId) { id author { name booksCount } inMyCart inMyReadList }
+		me { email cart { book { id inMyCart } } readList { book { id } } }
+	}`
+	vars := map[string]interface{}{"authorId": fmt.Sprint(author.Id)}
+
+	t.Run("authenticated", func(t *testing.T) {
+		res := query(t, h, books, vars, user.Token)
+		require.Empty(t, res.Errors)
+
+		inCart := map[string]bool{}
+		inReadList := map[string]bool{}
+		for _, v := range res.Data["books"].([]interface{}) {
+			b := v.(map[string]interface{})
+			require.Equal(t, map[string]interface{}{"name": author.Name, "booksCount": float64(2)}, b["author"])
+			inCart[b["id"].(string)] = b["inMyCart"].(bool)
+			inReadList[b["id"].(string)] = b["inMyReadList"].(bool)
+		}
+		require.Equal(t, map[string]bool{fmt.Sprint(book.ID): true, fmt.Sprint(other.ID): false}, inCart)
+		require.Equal(t, map[string]bool{fmt.Sprint(book.ID): false, fmt.Sprint(other.ID): true}, inReadList)
+
+		me := res.Data["me"].(map[string]interface{})
+		require.Equal(t, user.Email, me["email"])
+		require.Equal(t, []interface{}{map[string]interface{}{"book": map[string]interface{}{"id": fmt.Sprint(book.ID), "inMyCart": true}}}, me["cart"])
+		require.Equal(t, []interface{}{map[string]interface{}{"book": map[string]interface{}{"id": fmt.Sprint(other.ID)}}}, me["readList"])
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		res := query(t, h, books, vars, "")
+		require.Empty(t, res.Errors)
+		require.Nil(t, res.Data["me"])
+		for _, v := range res.Data["books"].([]interface{}) {
+			require.False(t, v.(map[string]interface{})["inMyCart"].(bool))
+		}
+	})
+
+	t.Run("get", func(t *testing.T) {
+		params := url.Values{"query": {`query($id: ID!) { book(id: $id) { title } }`}, "variables": {fmt.Sprintf(`{"id": "%d"}`, book.ID)}}
+		resp := h.Request(http.MethodGet, "/graphql?"+params.Encode(), nil, "")
+		var res response
+		apitest.Decode(t, resp, http.StatusOK, &res)
+		require.Equal(t, map[string]interface{}{"title": book.Title}, res.Data["book"])
+	})
+
+	t.Run("unknown book", func(t *testing.T) {
+		res := query(t, h, `{ book(id: "9999") { title } }`, nil, "")
+		require.Empty(t, res.Errors)
+		require.Nil(t, res.Data["book"])
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		res := query(t, h, `{ book(id: "first") { title } }`, nil, "")
+		require.Len(t, res.Errors, 1)
+		require.Equal(t, "invalid_params", res.Errors[0].Extensions["code"])
+	})
+}
+
+func TestGraphQLLimits(t *testing.T) {
+	h := apitest.New(t, func(c *config.Config) {
+		c.GraphQLMaxDepth = 3
+		c.GraphQLMaxComplexity = 50
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		code  string
+	}{
+		{"shallow", `{ books(limit: 10) { title author { name } } }`, ""},
+		{"too deep", `{ me { cart { book { author { name } } } } }`, "query_too_deep"},
+		{"too deep with fragments", `{ me { ...cart } } fragment cart on User { cart { book { author { name } } } }`, "query_too_deep"},
+		{"too complex", `{ books(limit: 100) { title } }`, "query_too_complex"},
+		{"too complex by default limit", `{ books { title author { name } } authors { name } }`, "query_too_complex"},
+		{"introspection", `{ __schema { types { name fields { name type { name ofType { name ofType { name } } } } } } }`, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res := query(t, h, tc.query, nil, "")
+			if tc.code == "" {
+				require.Empty(t, res.Errors)
+				return
+			}
+			require.Len(t, res.Errors, 1)
+			require.Equal(t, tc.code, res.Errors[0].Extensions["code"])
+			require.Nil(t, res.Data)
+		})
+	}
+}
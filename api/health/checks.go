@@ -0,0 +1,57 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/zura-t/bookstore_fiber/database"
+	"github.com/zura-t/bookstore_fiber/migrations"
+	"gorm.io/gorm"
+)
+
+// DatabaseCheck pings the primary and every replica.
+func DatabaseCheck(cluster *database.Cluster) Check {
+	return Check{"database", func(ctx context.Context) error {
+		for i, db := range append([]*gorm.DB{cluster.Primary}, cluster.Replicas...) {
+			sqlDB, err := db.DB()
+			if err == nil {
+				err = sqlDB.PingContext(ctx)
+			}
+			if err != nil && i == 0 {
+				return err
+			}
+			if err != nil {
+				return fmt.Errorf("replica %d: %w", i, err)
+			}
+		}
+		return nil
+	}}
+}
+
+// StorageCheck creates and removes a file in every directory, creating the
+// directory itself when missing.
+func StorageCheck(dirs ...string) Check {
+	return Check{"storage", func(ctx context.Context) error {
+		for _, dir := range dirs {
+			if err := os.MkdirAll(dir, 0o750); err != nil {
+				return err
+			}
+			f, err := os.CreateTemp(dir, ".readyz-*")
+			if err != nil {
+				return err
+			}
+			f.Close()
+			if err := os.Remove(f.Name()); err != nil {
+				return err
+			}
+		}
+		return nil
+	}}
+}
+
+// MigrationsCheck fails while migrations are pending, e.g. when a new
+// release is rolled out before "migrate up" ran.
+func MigrationsCheck(migrator *migrations.Migrator) Check {
+	return Check{"migrations", migrator.Check}
+}
@@ -0,0 +1,14 @@
+package health
+
+import "github.com/gofiber/fiber/v2"
+
+type StatusResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Liveness only tells that the process serves requests, so that a broken
+// dependency doesn't get the service restarted.
+func (r healthRouter) Liveness(c *fiber.Ctx) error {
+	return c.JSON(StatusResponse{Status: "ok"})
+}
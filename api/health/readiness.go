@@ -0,0 +1,37 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/middlewares/logger"
+)
+
+// CheckTimeout bounds a run of every check.
+const CheckTimeout = 2 * time.Second
+
+// Readiness runs every check and answers 503 when one of them fails, so the
+// load balancer stops sending traffic until the dependency recovers.
+func (r healthRouter) Readiness(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), CheckTimeout)
+	defer cancel()
+
+	res := StatusResponse{Status: "ok", Checks: map[string]string{}}
+	status := fiber.StatusOK
+	for _, check := range r.checks {
+		if err := check.Run(ctx); err != nil {
+			logger.Entry(c).WithFields(logrus.Fields{
+				"check": check.Name,
+			}).Error(err)
+			res.Checks[check.Name] = err.Error()
+			res.Status = "unavailable"
+			status = fiber.StatusServiceUnavailable
+			continue
+		}
+		res.Checks[check.Name] = "ok"
+	}
+
+	return c.Status(status).JSON(res)
+}
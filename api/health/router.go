@@ -0,0 +1,25 @@
+package health
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+// Check is one dependency the service needs to handle requests.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+type healthRouter struct {
+	log    *logrus.Logger
+	checks []Check
+}
+
+func NewHealthRouter(app *fiber.App, log *logrus.Logger, checks []Check) {
+	r := &healthRouter{log, checks}
+	app.Get("/healthz", r.Liveness)
+	app.Get("/readyz", r.Readiness)
+}
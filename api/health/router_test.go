@@ -0,0 +1,38 @@
+package health_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/apitest"
+	"github.com/zura-t/bookstore_fiber/api/health"
+)
+
+func TestHealth(t *testing.T) {
+	h := apitest.New(t)
+
+	apitest.Decode(t, h.Request(http.MethodGet, "/healthz", nil, ""), http.StatusOK, nil)
+
+	var ready health.StatusResponse
+	apitest.Decode(t, h.Request(http.MethodGet, "/readyz", nil, ""), http.StatusOK, &ready)
+	require.Equal(t, map[string]string{"database": "ok", "storage": "ok", "migrations": "ok"}, ready.Checks)
+
+	require.NoError(t, h.DB.Exec("DELETE FROM schema_migrations WHERE version = (SELECT MAX(version) FROM schema_migrations)").Error)
+	apitest.Decode(t, h.Request(http.MethodGet, "/readyz", nil, ""), http.StatusServiceUnavailable, &ready)
+	require.Equal(t, "unavailable", ready.Status)
+	require.NotEqual(t, "ok", ready.Checks["migrations"])
+	require.Equal(t, "ok", ready.Checks["storage"])
+}
+
+func TestStorageCheck(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, health.StorageCheck(dir).Run(context.Background()))
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o600))
+	require.Error(t, health.StorageCheck(file).Run(context.Background()))
+}
@@ -0,0 +1,298 @@
+// Package openapi builds the OpenAPI 3 document of the API. Routers describe
+// their routes with Route values next to the handlers, request and response
+// schemas are derived from the Go types, including the constraints of their
+// validate tags.
+package openapi
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/zura-t/bookstore_fiber/api/problem"
+)
+
+const Version = "3.0.3"
+
+// Security scheme names.
+const (
+	BearerAuth = "bearerAuth"
+	ApiKeyAuth = "apiKeyAuth"
+)
+
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Servers    []Server            `json:"servers,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Server is the base URL of the paths, e.g. the prefix of an API version.
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower case HTTP methods to operations.
+type PathItem map[string]*Operation
+
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes"`
+}
+
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type Operation struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]Response   `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                 `json:"required,omitempty"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string               `json:"description"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route documents one route. Request is a zero value of the struct the
+// handler binds, its params, query, json and form tags tell where each field
+// comes from. Response is a zero value of the body sent with Status: nil for
+// none, a string for text and []byte for a file of ContentType.
+type Route struct {
+	Method      string
+	Path        string
+	Tag         string
+	Summary     string
+	Description string
+	// Auth routes need an access token. With Scopes they also accept API
+	// keys that have all of them.
+	Auth bool
+	// OptionalAuth routes also serve anonymous clients and check the
+	// credentials that are sent like Auth routes.
+	OptionalAuth bool
+	Scopes       []string
+	Request      interface{}
+	Form         bool
+	Parameters   []Parameter
+	Status       int
+	Response     interface{}
+	// ContentType of the response, only needed for files.
+	ContentType string
+	// Errors are the statuses of the problems the route reports besides the
+	// ones every route of its kind can report.
+	Errors []int
+}
+
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)\??`)
+
+// Path converts a Fiber route path to an OpenAPI path, e.g. /users/:id to
+// /users/{id}.
+func Path(fiberPath string) string {
+	return pathParam.ReplaceAllString(fiberPath, "{$1}")
+}
+
+// New builds the document of routes.
+func New(info Info, routes ...[]Route) (*Document, error) {
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   make(map[string]PathItem),
+		Components: Components{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]SecurityScheme{
+				BearerAuth: {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Access token returned by /login"},
+				ApiKeyAuth: {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "Personal API key, it can also be sent as a Bearer token"},
+			},
+		},
+	}
+	g := &generator{schemas: doc.Components.Schemas, types: make(map[string]reflect.Type)}
+	g.problem = g.schema(reflect.TypeOf(problem.Problem{}))
+
+	for _, group := range routes {
+		for _, route := range group {
+			path := Path(route.Path)
+			item, ok := doc.Paths[path]
+			if !ok {
+				item = make(PathItem)
+				doc.Paths[path] = item
+			}
+			method := strings.ToLower(route.Method)
+			if _, ok := item[method]; ok {
+				return nil, fmt.Errorf("openapi: %s %s is documented twice", route.Method, route.Path)
+			}
+			op, err := g.operation(route)
+			if err != nil {
+				return nil, fmt.Errorf("openapi: %s %s: %w", route.Method, route.Path, err)
+			}
+			item[method] = op
+		}
+	}
+	return doc, nil
+}
+
+func (g *generator) operation(route Route) (*Operation, error) {
+	op := &Operation{
+		Summary:     route.Summary,
+		Description: route.Description,
+		OperationID: operationID(route),
+		Responses:   make(map[string]Response),
+	}
+	if route.Tag != "" {
+		op.Tags = []string{route.Tag}
+	}
+
+	if route.Request != nil {
+		params, body, err := g.request(reflect.TypeOf(route.Request), route.Form)
+		if err != nil {
+			return nil, err
+		}
+		op.Parameters = params
+		op.RequestBody = body
+	}
+	op.Parameters = append(op.Parameters, route.Parameters...)
+
+	// Path parameters the request struct doesn't bind are read as strings.
+	for _, match := range pathParam.FindAllStringSubmatch(route.Path, -1) {
+		if !hasParameter(op.Parameters, match[1], "path") {
+			op.Parameters = append(op.Parameters, Parameter{Name: match[1], In: "path", Required: true, Schema: &Schema{Type: "string"}})
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	op.Responses[strconv.Itoa(status)] = g.response(status, route.Response, route.ContentType)
+
+	errors := append([]int(nil), route.Errors...)
+	if route.Request != nil || len(op.Parameters) > 0 {
+		errors = append(errors, http.StatusBadRequest)
+	}
+	if route.Auth || route.OptionalAuth {
+		errors = append(errors, http.StatusUnauthorized, http.StatusForbidden)
+		op.Security = []map[string][]string{{BearerAuth: {}}}
+		if len(route.Scopes) > 0 {
+			op.Security = append(op.Security, map[string][]string{ApiKeyAuth: {}})
+			op.Description = strings.TrimSpace(op.Description + "\n\nAPI keys need the " + strings.Join(route.Scopes, ", ") + " scope.")
+		}
+		if route.OptionalAuth {
+			op.Security = append(op.Security, map[string][]string{})
+		}
+	}
+	errors = append(errors, http.StatusTooManyRequests)
+	for _, code := range errors {
+		res := Response{
+			Description: http.StatusText(code),
+			Content:     map[string]MediaType{problem.ContentType: {Schema: g.problem}},
+		}
+		if code == http.StatusTooManyRequests {
+			res.Headers = map[string]Header{"Retry-After": {Description: "Seconds until the next request is allowed", Schema: &Schema{Type: "integer"}}}
+		}
+		op.Responses[strconv.Itoa(code)] = res
+	}
+	op.Responses["default"] = Response{
+		Description: "Unexpected error",
+		Content:     map[string]MediaType{problem.ContentType: {Schema: g.problem}},
+	}
+	return op, nil
+}
+
+func (g *generator) response(status int, body interface{}, contentType string) Response {
+	res := Response{Description: http.StatusText(status)}
+	if status >= 300 && status < 400 {
+		res.Headers = map[string]Header{"Location": {Schema: &Schema{Type: "string", Format: "uri"}}}
+	}
+	switch body := body.(type) {
+	case nil:
+	case string:
+		res.Content = map[string]MediaType{"text/plain": {Schema: &Schema{Type: "string"}}}
+	case []byte:
+		res.Content = map[string]MediaType{contentType: {Schema: &Schema{Type: "string", Format: "binary"}}}
+	default:
+		res.Content = map[string]MediaType{"application/json": {Schema: g.schema(reflect.TypeOf(body))}}
+	}
+	return res
+}
+
+// operationID is the method and path in camel case, e.g. getUsersId.
+func operationID(route Route) string {
+	id := strings.ToLower(route.Method)
+	for _, part := range strings.FieldsFunc(route.Path, func(r rune) bool { return r == '/' || r == ':' || r == '_' || r == '-' }) {
+		id += strings.ToUpper(part[:1]) + part[1:]
+	}
+	return id
+}
+
+func hasParameter(params []Parameter, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+	return false
+}
+
+// Operations lists the documented routes as "METHOD /path", sorted.
+func (d *Document) Operations() []string {
+	var ops []string
+	for path, item := range d.Paths {
+		for method := range item {
+			ops = append(ops, strings.ToUpper(method)+" "+path)
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
+
+// PageParameters are the limit and offset query parameters of handlers that
+// read them without a request struct.
+var PageParameters = []Parameter{
+	{Name: "limit", In: "query", Description: "Defaults to 20", Schema: &Schema{Type: "integer"}},
+	{Name: "offset", In: "query", Description: "Defaults to 0", Schema: &Schema{Type: "integer"}},
+}
@@ -0,0 +1,261 @@
+package openapi
+
+import (
+	"fmt"
+	"mime/multipart"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/i18n"
+	"github.com/zura-t/bookstore_fiber/pkg"
+)
+
+type Schema struct {
+	Ref         string             `json:"$ref,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Format      string             `json:"format,omitempty"`
+	Description string             `json:"description,omitempty"`
+	Nullable    bool               `json:"nullable,omitempty"`
+	Properties  map[string]*Schema `json:"properties,omitempty"`
+	Required    []string           `json:"required,omitempty"`
+	Items       *Schema            `json:"items,omitempty"`
+	Enum        []string           `json:"enum,omitempty"`
+	Pattern     string             `json:"pattern,omitempty"`
+	Minimum     *float64           `json:"minimum,omitempty"`
+	Maximum     *float64           `json:"maximum,omitempty"`
+	MinLength   *int               `json:"minLength,omitempty"`
+	MaxLength   *int               `json:"maxLength,omitempty"`
+	MinItems    *int               `json:"minItems,omitempty"`
+	MaxItems    *int               `json:"maxItems,omitempty"`
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	fileType = reflect.TypeOf(multipart.FileHeader{})
+)
+
+type generator struct {
+	schemas map[string]*Schema
+	// types remembers the type of every component, so that two types with
+	// the same name don't overwrite each other.
+	types   map[string]reflect.Type
+	problem *Schema
+}
+
+// schema returns the schema of t. Named structs become components and are
+// referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case fileType:
+		return &Schema{Type: "string", Format: "binary"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if s.Ref != "" || s.Format == "binary" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer", Minimum: float(0)}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object"}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t, "json")
+		}
+		name := componentName(t)
+		if known, ok := g.types[name]; ok && known != t {
+			name = componentName(t, path.Base(t.PkgPath()))
+		}
+		if _, ok := g.types[name]; !ok {
+			g.types[name] = t
+			g.schemas[name] = g.object(t, "json")
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+// object describes the fields of t named by tag, embedded structs are
+// flattened like encoding/json does.
+func (g *generator) object(t reflect.Type, tag string) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for _, field := range fields(t) {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "" {
+			// Parameters aren't part of the body, untagged fields are with
+			// their Go name in JSON only.
+			if tag != "json" || field.Tag.Get("params") != "" || field.Tag.Get("query") != "" {
+				continue
+			}
+			name = field.Name
+		}
+		if name == "-" {
+			continue
+		}
+		prop, required := g.field(field)
+		s.Properties[name] = prop
+		if required {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// fields returns the exported fields of t with the ones of embedded structs.
+func fields(t reflect.Type) []reflect.StructField {
+	var res []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			res = append(res, fields(field.Type)...)
+			continue
+		}
+		if field.IsExported() {
+			res = append(res, field)
+		}
+	}
+	return res
+}
+
+func (g *generator) field(field reflect.StructField) (*Schema, bool) {
+	s := g.schema(field.Type)
+	if s.Ref != "" {
+		return s, false
+	}
+	return s, constrain(s, field.Tag.Get("validate"))
+}
+
+// constrain adds the validate rules OpenAPI can express to s and reports
+// whether the field is required.
+func constrain(s *Schema, rules string) bool {
+	required := false
+	for _, rule := range strings.Split(rules, ",") {
+		tag, param, _ := strings.Cut(rule, "=")
+		switch tag {
+		case "required":
+			required = true
+			if s.Type == "string" && s.MinLength == nil {
+				s.MinLength = integer(1)
+			}
+		case "min", "gte":
+			n, _ := strconv.Atoi(param)
+			bound(s, n, true)
+		case "max", "lte":
+			n, _ := strconv.Atoi(param)
+			bound(s, n, false)
+		case "email":
+			s.Format = "email"
+		case "url", "http_url", "uri":
+			s.Format = "uri"
+		case "uuid", "uuid4":
+			s.Format = "uuid"
+		case "oneof":
+			s.Enum = strings.Fields(param)
+		case "slug":
+			s.Pattern = `^[a-z0-9]+(?:-[a-z0-9]+)*$`
+		case "price":
+			s.Minimum, s.Maximum = float(1), float(pkg.MaxPrice)
+		case "locale":
+			s.Enum = i18n.Supported()
+		}
+	}
+	return required
+}
+
+// bound sets a lower or upper bound, which validator applies to the length
+// of strings, the size of collections and the value of numbers.
+func bound(s *Schema, n int, lower bool) {
+	switch s.Type {
+	case "string":
+		if lower {
+			s.MinLength = integer(n)
+		} else {
+			s.MaxLength = integer(n)
+		}
+	case "array":
+		if lower {
+			s.MinItems = integer(n)
+		} else {
+			s.MaxItems = integer(n)
+		}
+	default:
+		if lower {
+			s.Minimum = float(float64(n))
+		} else {
+			s.Maximum = float(float64(n))
+		}
+	}
+}
+
+// request splits the fields of a request struct into parameters and a body,
+// the way pkg.Bind reads them.
+func (g *generator) request(t reflect.Type, form bool) ([]Parameter, *RequestBody, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, nil, fmt.Errorf("request must be a struct, got %s", t)
+	}
+
+	var params []Parameter
+	hasBody := false
+	for _, field := range fields(t) {
+		for _, in := range []string{"params", "query"} {
+			name, _, _ := strings.Cut(field.Tag.Get(in), ",")
+			if name == "" {
+				continue
+			}
+			schema, required := g.field(field)
+			location := "query"
+			if in == "params" {
+				location, required = "path", true
+			}
+			params = append(params, Parameter{Name: name, In: location, Required: required, Schema: schema})
+		}
+		if field.Tag.Get("json") != "" || field.Tag.Get("form") != "" {
+			hasBody = true
+		}
+	}
+	if !hasBody {
+		return params, nil, nil
+	}
+
+	body := &RequestBody{Required: true, Content: make(map[string]MediaType)}
+	if form {
+		body.Content["multipart/form-data"] = MediaType{Schema: g.object(t, "form")}
+	} else {
+		body.Content["application/json"] = MediaType{Schema: g.object(t, "json")}
+	}
+	return params, body, nil
+}
+
+// componentName is the type name, capitalized because unexported types are
+// documented too, after the optional package name.
+func componentName(t reflect.Type, prefix ...string) string {
+	name := strings.Join(prefix, "") + t.Name()
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func integer(n int) *int {
+	return &n
+}
+
+func float(n float64) *float64 {
+	return &n
+}
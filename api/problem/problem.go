@@ -0,0 +1,88 @@
+// Package problem reports errors as RFC 7807 problem details.
+package problem
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/i18n"
+	"github.com/zura-t/bookstore_fiber/middlewares/logger"
+)
+
+const ContentType = "application/problem+json"
+
+// Problem is the body of every error response. Code is the stable
+// identifier clients should match on, Errors lists invalid request fields.
+type Problem struct {
+	Type      string                `json:"type"`
+	Title     string                `json:"title"`
+	Status    int                   `json:"status"`
+	Detail    string                `json:"detail,omitempty"`
+	Instance  string                `json:"instance,omitempty"`
+	Code      string                `json:"code"`
+	RequestID string                `json:"request_id,omitempty"`
+	Errors    []apperror.FieldError `json:"errors,omitempty"`
+}
+
+var statuses = map[apperror.Kind]int{
+	apperror.Internal:        fiber.StatusInternalServerError,
+	apperror.Invalid:         fiber.StatusBadRequest,
+	apperror.Unauthorized:    fiber.StatusUnauthorized,
+	apperror.Forbidden:       fiber.StatusForbidden,
+	apperror.NotFound:        fiber.StatusNotFound,
+	apperror.Conflict:        fiber.StatusConflict,
+	apperror.Gone:            fiber.StatusGone,
+	apperror.TooManyRequests: fiber.StatusTooManyRequests,
+	apperror.Unavailable:     fiber.StatusServiceUnavailable,
+	apperror.BadGateway:      fiber.StatusBadGateway,
+}
+
+// ErrorHandler is the Fiber error handler. Application errors are
+// translated to the negotiated locale, Fiber errors such as unknown routes
+// keep their status and message, anything else is an internal error whose
+// details are only logged.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	p := Problem{Instance: c.Path(), RequestID: c.GetRespHeader(logger.HeaderRequestID)}
+
+	var appErr *apperror.Error
+	var fiberErr *fiber.Error
+	if !errors.As(err, &appErr) && !errors.As(err, &fiberErr) {
+		appErr = apperror.ErrInternal
+	}
+	if appErr != nil {
+		locale := i18n.Locale(c)
+		c.Set(fiber.HeaderContentLanguage, locale)
+
+		p.Status = statuses[appErr.Kind]
+		p.Code = appErr.Code
+		p.Detail = translate(locale, "errors."+appErr.Code, appErr.Message, appErr.Params)
+		for _, field := range appErr.Fields {
+			field.Message = translate(locale, field.Key, field.Message, field.Params)
+			p.Errors = append(p.Errors, field)
+		}
+	} else {
+		p.Status = fiberErr.Code
+		p.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(fiberErr.Code), " ", "_"))
+		p.Detail = fiberErr.Message
+	}
+	p.Type = "/problems/" + p.Code
+	p.Title = http.StatusText(p.Status)
+
+	if p.Status >= fiber.StatusInternalServerError {
+		logger.Entry(c).WithField("code", p.Code).Error(err)
+	} else {
+		logger.Entry(c).WithField("code", p.Code).Info(err)
+	}
+
+	return c.Status(p.Status).JSON(p, ContentType)
+}
+
+func translate(locale, key, fallback string, params []string) string {
+	if text, ok := i18n.T(locale, key, params...); ok {
+		return text
+	}
+	return fallback
+}
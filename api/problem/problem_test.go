@@ -0,0 +1,81 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/i18n"
+	"github.com/zura-t/bookstore_fiber/pkg"
+)
+
+func TestErrorHandler(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/invalid", func(c *fiber.Ctx) error {
+		return apperror.ErrValidation.WithFields([]apperror.FieldError{{Field: "email", Message: "Invalid email"}})
+	})
+	app.Get("/internal", func(c *fiber.Ctx) error {
+		return errors.New("pq: relation \"users\" does not exist")
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		code   string
+		detail string
+		fields int
+	}{
+		{"/invalid", http.StatusBadRequest, "validation_failed", "Request validation failed", 1},
+		{"/internal", http.StatusInternalServerError, "internal_error", "Internal server error", 0},
+		{"/unknown", http.StatusNotFound, "not_found", "Cannot GET /unknown", 0},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(http.MethodGet, tc.path, nil))
+			require.NoError(t, err)
+			require.Equal(t, tc.status, resp.StatusCode)
+			require.Equal(t, ContentType, resp.Header.Get(fiber.HeaderContentType))
+
+			var p Problem
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+			require.Equal(t, tc.status, p.Status)
+			require.Equal(t, tc.code, p.Code)
+			require.Equal(t, "/problems/"+tc.code, p.Type)
+			require.Equal(t, tc.detail, p.Detail)
+			require.Equal(t, tc.path, p.Instance)
+			require.Len(t, p.Errors, tc.fields)
+		})
+	}
+}
+
+func TestErrorHandlerTranslates(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: ErrorHandler})
+	app.Get("/invalid", func(c *fiber.Ctx) error {
+		return pkg.Validate(&struct {
+			Email string `json:"email" validate:"required"`
+		}{})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/invalid", nil)
+	req.Header.Set(fiber.HeaderAcceptLanguage, "ru")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, "ru", resp.Header.Get(fiber.HeaderContentLanguage))
+
+	var p Problem
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&p))
+	require.Equal(t, "validation_failed", p.Code)
+	require.Len(t, p.Errors, 1)
+
+	detail, _ := i18n.T("ru", "errors.validation_failed")
+	field, _ := i18n.T("ru", "validation.required", "email", "")
+	require.Equal(t, detail, p.Detail)
+	require.Equal(t, field, p.Errors[0].Message)
+	require.NotEqual(t, "email is required", p.Errors[0].Message)
+}
@@ -1,31 +1,121 @@
 package api
 
 import (
+	"fmt"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/api/book"
-	"github.com/zura-t/bookstore_fiber/api/cart"
-	"github.com/zura-t/bookstore_fiber/api/user"
+	"github.com/zura-t/bookstore_fiber/api/graphql"
+	"github.com/zura-t/bookstore_fiber/api/health"
+	"github.com/zura-t/bookstore_fiber/api/problem"
 	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/database"
+	"github.com/zura-t/bookstore_fiber/i18n"
+	"github.com/zura-t/bookstore_fiber/metrics"
+	"github.com/zura-t/bookstore_fiber/middlewares/deprecation"
+	"github.com/zura-t/bookstore_fiber/middlewares/logger"
+	"github.com/zura-t/bookstore_fiber/middlewares/ratelimit"
+	"github.com/zura-t/bookstore_fiber/service"
 	"github.com/zura-t/bookstore_fiber/token"
+	"github.com/zura-t/bookstore_fiber/tracing"
 	"gorm.io/gorm"
 )
 
-func NewRouter(app *fiber.App, log *logrus.Logger, config config.Config, db *gorm.DB) {
-	app.Get("/", func(c *fiber.Ctx) error {
-		return c.SendString("Hello, World!")
+// NewApp creates the Fiber app. Requests from the trusted proxies report the
+// client IP from the proxy header, to the rate limits and the logs.
+func NewApp(config config.Config) *fiber.App {
+	return fiber.New(fiber.Config{
+		ErrorHandler:            problem.ErrorHandler,
+		ProxyHeader:             config.ProxyHeader,
+		EnableTrustedProxyCheck: len(config.TrustedProxies) > 0,
+		TrustedProxies:          config.TrustedProxies,
+		EnableIPValidation:      true,
 	})
+}
 
-	token, err := token.NewJwtMaker(log, config.TokenKey)
-	if err != nil {
-		log.WithFields(logrus.Fields{
-			"level": "Fatal",
-		}).Fatal(err)
+func NewRouter(app *fiber.App, log *logrus.Logger, config config.Config, cluster *database.Cluster, deps Dependencies) {
+	metrics := metrics.New()
+	app.Use(metrics.Middleware())
+	app.Use(tracing.Middleware())
+	app.Use(logger.New(log))
+	app.Get("/metrics", metrics.Handler(config.MetricsToken))
+
+	dbs := append([]*gorm.DB{cluster.Primary}, cluster.Replicas...)
+	for i, db := range dbs {
+		name := "primary"
+		if i > 0 {
+			name = fmt.Sprintf("replica-%d", i)
+		}
+		if err := metrics.Instrument(db); err != nil {
+			log.Fatal(err)
+		}
+		if err := tracing.Instrument(db); err != nil {
+			log.Fatal(err)
+		}
+		if err := metrics.RegisterPool(name, db); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := metrics.RegisterBusinessGauges(cluster.Primary); err != nil {
+		log.Fatal(err)
 	}
 
+	services, token := deps.Services, deps.Maker
+
+	app.Use(i18n.Middleware(localePreference(services.Users)))
+
 	{
-		user.NewuserRouter(app, log, config, db, token)
-		book.NewBookRouter(app, log, config, db, token)
-		cart.NewCartRouter(app, log, config, db, token)
+		// Probes and docs are registered before the rate limits, so they are
+		// never throttled.
+		health.NewHealthRouter(app, log, deps.Checks)
+		if err := newDocsRouter(app); err != nil {
+			log.Fatal(err)
+		}
+
+		limiter := newRateLimiter(config, cluster.Primary, token, services.ApiKeys)
+		if limiter != nil {
+			app.Use(limiter.Handler("default", config.RateLimitDefault))
+		}
+
+		// The GraphQL schema evolves by adding fields and deprecating old
+		// ones instead of by version, so it has a single unprefixed route.
+		if err := graphql.NewGraphQLRouter(app, log, config, services, token); err != nil {
+			log.Fatal(err)
+		}
+
+		v1 := versionRoutes{log, config, services, token, metrics, limiter}
+		v1.mountV1(app.Group("/v1"))
+		// The unprefixed routes are aliases of v1 for the clients written
+		// before versioning. They go last, so that only requests no other
+		// route matched reach the deprecation middleware.
+		v1.mountV1(app.Group("", deprecation.New(config.LegacyDeprecatedAt, config.LegacySunset, "/v1")))
+	}
+}
+
+// newRateLimiter returns nil when rate limiting is off.
+func newRateLimiter(config config.Config, db *gorm.DB, maker *token.JwtMaker, apiKeys *service.ApiKeyService) *ratelimit.Limiter {
+	switch config.RateLimitStore {
+	case "memory":
+		return ratelimit.New(ratelimit.NewMemoryStore(), ratelimit.ByClient(maker, apiKeys))
+	case "database":
+		return ratelimit.New(ratelimit.NewDatabaseStore(db), ratelimit.ByClient(maker, apiKeys))
+	}
+	return nil
+}
+
+// localePreference returns the locale saved in the profile of the
+// authenticated user. It is only called when an error is translated, by then
+// the auth middleware has stored the user.
+func localePreference(users *service.UserService) func(c *fiber.Ctx) string {
+	return func(c *fiber.Ctx) string {
+		data, ok := c.Locals("user").(*token.Payload)
+		if !ok {
+			return ""
+		}
+		user, err := users.Get(c.UserContext(), data.UserId)
+		if err != nil {
+			return ""
+		}
+		return user.Locale
 	}
 }
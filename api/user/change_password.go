@@ -0,0 +1,33 @@
+package user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+type ChangePasswordRequest struct {
+	OldPassword string `json:"old_password" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required"`
+}
+
+func (r *userRouter) ChangePassword(c *fiber.Ctx) error {
+	payload := c.Locals("user")
+	data, ok := payload.(*token.Payload)
+	if !ok {
+		return apperror.ErrUnauthenticated
+	}
+
+	var req = &ChangePasswordRequest{}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
+	}
+
+	err := r.users.ChangePassword(c.UserContext(), data.UserId, req.OldPassword, req.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return c.SendString("Password changed")
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+type CreateApiKeyRequest struct {
+	Name          string   `json:"name" validate:"required,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"min=0,max=365"`
+}
+
+type ApiKeyResponse struct {
+	Id         uint       `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type CreateApiKeyResponse struct {
+	ApiKeyResponse
+	Key string `json:"key"`
+}
+
+func (r *userRouter) CreateApiKey(c *fiber.Ctx) error {
+	payload := c.Locals("user")
+	data, ok := payload.(*token.Payload)
+	if !ok {
+		return apperror.ErrUnauthenticated
+	}
+
+	var req = &CreateApiKeyRequest{}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
+	}
+
+	apiKey, key, err := r.apiKeys.Create(c.UserContext(), data.UserId, req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		return err
+	}
+
+	res := CreateApiKeyResponse{
+		ApiKeyResponse: ConvertApiKey(*apiKey),
+		Key:            key,
+	}
+	return c.Status(fiber.StatusCreated).JSON(res)
+}
+
+func ConvertApiKey(key entity.ApiKey) ApiKeyResponse {
+	return ApiKeyResponse{
+		Id:         key.Id,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.Scopes,
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
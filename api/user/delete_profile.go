@@ -3,31 +3,22 @@ package user
 import (
 	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
-	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/token"
+	"time"
 )
 
 func (r *userRouter) DeleteMyProfile(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
-	var user models.User
-	err := r.db.Delete(&user, data.UserId).Error
+	restoreUntil, err := r.users.Delete(c.UserContext(), data.UserId)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
-	return c.SendString("Profile deleted")
+	return c.SendString(fmt.Sprintf("Profile deleted, it can be restored until %s", restoreUntil.Format(time.RFC3339)))
 }
@@ -0,0 +1,24 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (r *userRouter) DownloadExport(c *fiber.Ctx) error {
+	export, err := r.findExport(c, r.exports.Download)
+	if err != nil {
+		return err
+	}
+
+	_, span := tracing.Start(c.UserContext(), "storage.send", attribute.String("file.path", export.File))
+	err = c.Download(export.File, fmt.Sprintf("bookstore-export-%s.zip", export.CreatedAt.Format("2006-01-02")))
+	tracing.End(span, err)
+	if err == nil {
+		r.metrics.Download("export", c.Response().Header.ContentLength())
+	}
+	return err
+}
@@ -0,0 +1,27 @@
+package user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+func (r *userRouter) GetApiKeys(c *fiber.Ctx) error {
+	payload := c.Locals("user")
+	data, ok := payload.(*token.Payload)
+	if !ok {
+		return apperror.ErrUnauthenticated
+	}
+
+	keys, err := r.apiKeys.List(c.UserContext(), data.UserId)
+	if err != nil {
+		return err
+	}
+
+	res := make([]*ApiKeyResponse, len(keys))
+	for index, v := range keys {
+		key := ConvertApiKey(v)
+		res[index] = &key
+	}
+	return c.JSON(res)
+}
@@ -0,0 +1,42 @@
+package user
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+type ExportId struct {
+	Id string `params:"id" validate:"required,uuid"`
+}
+
+func (r *userRouter) GetExport(c *fiber.Ctx) error {
+	export, err := r.findExport(c, r.exports.Get)
+	if err != nil {
+		return err
+	}
+
+	res := ConvertExport(*export)
+	return c.JSON(res)
+}
+
+// findExport loads the export from the :id param with find, restricted to the
+// exports of the current user.
+func (r *userRouter) findExport(c *fiber.Ctx, find func(ctx context.Context, userId uint, id string) (*entity.DataExport, error)) (*entity.DataExport, error) {
+	payload := c.Locals("user")
+	data, ok := payload.(*token.Payload)
+	if !ok {
+		return nil, apperror.ErrUnauthenticated
+	}
+
+	var req = &ExportId{}
+	if err := pkg.Bind(c, req); err != nil {
+		return nil, err
+	}
+
+	return find(c.UserContext(), data.UserId, req.Id)
+}
@@ -2,23 +2,16 @@ package user
 
 import (
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
-	"github.com/zura-t/bookstore_fiber/pkg"
 )
 
 type UserId struct {
-	Id uint `uri:"id" json:"id" validate:"required,min=1"`
+	Id uint `params:"id" validate:"required,min=1"`
 }
 
 func (r *userRouter) GetUsers(c *fiber.Ctx) error {
-	var users []models.User
-	err := r.db.Find(&users).Error
+	users, err := r.users.List(c.UserContext())
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	res := make([]*UserResponse, len(users))
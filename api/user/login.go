@@ -1,14 +1,10 @@
 package user
 
 import (
-	"errors"
 	"fmt"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/entity"
 	"github.com/zura-t/bookstore_fiber/pkg"
-	"gorm.io/gorm"
 	"time"
 )
 
@@ -27,80 +23,42 @@ type LoginUserResponse struct {
 
 func (r *userRouter) Login(c *fiber.Ctx) error {
 	var req = &LoginUserRequest{}
-	if err := c.BodyParser(req); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusServiceUnavailable).JSON(pkg.ErrorResponse(err))
+	if err := pkg.Bind(c, req); err != nil {
+		return err
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(req); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(req, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-
-	var user models.User
-	err := r.db.First(&user, models.User{Email: req.Email}).Error
+	user, err := r.users.Authenticate(c.UserContext(), req.Email, req.Password)
+	r.metrics.Login("password", err == nil)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			err = fmt.Errorf("User not found")
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(err)
-			return c.Status(fiber.StatusNotFound).JSON(pkg.ErrorResponse(err))
-		}
-
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
-	err = pkg.CheckPassword(req.Password, user.Password)
+	res, err := r.createSession(*user)
 	if err != nil {
-		err = fmt.Errorf("Error incorrect password, %s", err)
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
-	accessToken, accessPayload, err := r.token.CreateToken(user.ID, user.Email, r.config.AccessTokenDuration)
+	return c.JSON(res)
+}
+
+// createSession issues the access and refresh tokens returned by every login
+// method.
+func (r *userRouter) createSession(user entity.User) (*LoginUserResponse, error) {
+	accessToken, accessPayload, err := r.token.CreateToken(user.Id, user.Email, r.config.AccessTokenDuration)
 	if err != nil {
-		err = fmt.Errorf("failed to create access token: %s", err)
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return nil, fmt.Errorf("failed to create access token: %s", err)
 	}
 
-	refreshToken, refreshPayload, err := r.token.CreateToken(user.ID, user.Email, r.config.RefreshTokenDuration)
+	refreshToken, refreshPayload, err := r.token.CreateToken(user.Id, user.Email, r.config.RefreshTokenDuration)
 	if err != nil {
-		err = fmt.Errorf("failed to create refresh token: %s", err)
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return nil, fmt.Errorf("failed to create refresh token: %s", err)
 	}
 
-	res := LoginUserResponse{
+	return &LoginUserResponse{
 		User:                  ConvertUser(user),
 		AccessToken:           accessToken,
 		AccessTokenExpiresAt:  accessPayload.ExpiredAt,
 		RefreshToken:          refreshToken,
 		RefreshTokenExpiresAt: refreshPayload.ExpiredAt,
-	}
-
-	return c.JSON(res)
+	}, nil
 }
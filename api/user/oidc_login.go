@@ -0,0 +1,94 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
+)
+
+var (
+	ErrUnknownProvider     = apperror.New(apperror.NotFound, "unknown_identity_provider", "Unknown identity provider")
+	ErrProviderUnavailable = apperror.New(apperror.BadGateway, "identity_provider_unavailable", "Identity provider is unavailable")
+	ErrAuthorizationFailed = apperror.New(apperror.Invalid, "authorization_failed", "Authorization failed")
+	ErrIdentityNotVerified = apperror.New(apperror.Unauthorized, "identity_not_verified", "Identity could not be verified")
+)
+
+// OidcLogin starts the authorization code flow with PKCE and redirects the
+// client to the identity provider.
+func (r *userRouter) OidcLogin(c *fiber.Ctx) error {
+	return r.startOidcLogin(c, false)
+}
+
+// OidcRestore starts a login that restores the deleted account linked to the
+// identity, the way accounts created through OIDC, which have no password,
+// are restored during the grace period.
+func (r *userRouter) OidcRestore(c *fiber.Ctx) error {
+	return r.startOidcLogin(c, true)
+}
+
+func (r *userRouter) startOidcLogin(c *fiber.Ctx, restore bool) error {
+	provider, ok := r.providers[c.Params("provider")]
+	if !ok {
+		return ErrUnknownProvider
+	}
+
+	state, err := r.identities.NewLoginState(c.UserContext(), provider.Name(), restore)
+	if err != nil {
+		return err
+	}
+
+	authURL, err := provider.AuthCodeURL(c.UserContext(), state.State, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		return ErrProviderUnavailable.Wrap(err)
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// OidcCallback completes the login, links the external identity to a user, or
+// restores it for OidcRestore, and issues the same tokens as Login.
+func (r *userRouter) OidcCallback(c *fiber.Ctx) error {
+	provider, ok := r.providers[c.Params("provider")]
+	if !ok {
+		return ErrUnknownProvider
+	}
+
+	if errCode := c.Query("error"); errCode != "" {
+		return ErrAuthorizationFailed.WithMessage(fmt.Sprintf("Authorization failed: %s %s", errCode, c.Query("error_description"))).WithParams(errCode)
+	}
+
+	state, err := r.identities.TakeLoginState(c.UserContext(), provider.Name(), c.Query("state"))
+	if err != nil {
+		return err
+	}
+
+	tokens, err := provider.Exchange(c.UserContext(), c.Query("code"), state.CodeVerifier)
+	if err != nil {
+		return ErrIdentityNotVerified.Wrap(err)
+	}
+
+	claims, err := provider.VerifyIDToken(c.UserContext(), tokens.IDToken, state.Nonce)
+	if err != nil {
+		return ErrIdentityNotVerified.Wrap(err)
+	}
+
+	var user *entity.User
+	if state.Restore {
+		user, err = r.identities.Restore(c.UserContext(), provider.Name(), claims)
+	} else {
+		user, err = r.identities.Link(c.UserContext(), provider.Name(), claims)
+	}
+	r.metrics.Login(provider.Name(), err == nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.createSession(*user)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(res)
+}
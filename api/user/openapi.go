@@ -0,0 +1,51 @@
+package user
+
+import (
+	"net/http"
+
+	"github.com/zura-t/bookstore_fiber/api/openapi"
+)
+
+var refreshTokenCookie = openapi.Parameter{
+	Name:        "refresh_token",
+	In:          "cookie",
+	Description: "Refresh token returned by /login",
+	Required:    true,
+	Schema:      &openapi.Schema{Type: "string"},
+}
+
+// Routes documents the routes registered by NewuserRouter.
+var Routes = []openapi.Route{
+	{Method: http.MethodPost, Path: "/register", Tag: "auth", Summary: "Register a user", Request: RegisterUserRequest{}, Response: UserResponse{}, Errors: []int{http.StatusConflict}},
+	{Method: http.MethodPost, Path: "/login", Tag: "auth", Summary: "Log in with email and password", Request: LoginUserRequest{}, Response: LoginUserResponse{}, Errors: []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/renew_token", Tag: "auth", Summary: "Issue a new access token", Parameters: []openapi.Parameter{refreshTokenCookie}, Response: renewAccessTokenResponse{}, Errors: []int{http.StatusUnauthorized}},
+	{Method: http.MethodPost, Path: "/logout", Tag: "auth", Summary: "Clear the refresh token cookie", Response: ""},
+	{Method: http.MethodPost, Path: "/users/restore", Tag: "auth", Summary: "Restore a deleted account during its grace period", Request: LoginUserRequest{}, Response: UserResponse{}, Errors: []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusGone}},
+	{Method: http.MethodGet, Path: "/auth/:provider/login", Tag: "auth", Summary: "Start an OpenID Connect login", Description: "Redirects to the identity provider.", Status: http.StatusFound, Errors: []int{http.StatusNotFound, http.StatusBadGateway}},
+	{Method: http.MethodGet, Path: "/auth/:provider/restore", Tag: "auth", Summary: "Restore a deleted account with an OpenID Connect login", Description: "For accounts without a password. Redirects to the identity provider, the callback restores the account during its grace period and logs in.", Status: http.StatusFound, Errors: []int{http.StatusNotFound, http.StatusBadGateway}},
+	{
+		Method: http.MethodGet, Path: "/auth/:provider/callback", Tag: "auth", Summary: "Complete an OpenID Connect login",
+		Parameters: []openapi.Parameter{
+			{Name: "code", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "state", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "error", In: "query", Schema: &openapi.Schema{Type: "string"}},
+			{Name: "error_description", In: "query", Schema: &openapi.Schema{Type: "string"}},
+		},
+		Response: LoginUserResponse{},
+		Errors:   []int{http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound, http.StatusGone},
+	},
+
+	{Method: http.MethodGet, Path: "/users", Tag: "users", Summary: "List users", Auth: true, Response: []UserResponse{}},
+	{Method: http.MethodGet, Path: "/users/:id", Tag: "users", Summary: "Get a user", Auth: true, Request: UserId{}, Response: UserResponse{}, Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/users/my_profile", Tag: "profile", Summary: "Get the profile of the current user", Auth: true, Response: UserResponse{}, Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPatch, Path: "/users/my_profile", Tag: "profile", Summary: "Update the name and locale", Auth: true, Request: UserUpdate{}, Response: UserResponse{}, Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodDelete, Path: "/users/my_profile", Tag: "profile", Summary: "Delete the account", Description: "The account can be restored until the grace period ends.", Auth: true, Response: ""},
+	{Method: http.MethodPatch, Path: "/users/my_profile/password", Tag: "profile", Summary: "Change the password", Auth: true, Request: ChangePasswordRequest{}, Response: ""},
+	{Method: http.MethodPatch, Path: "/users/author", Tag: "profile", Summary: "Become an author", Auth: true, Response: ""},
+	{Method: http.MethodGet, Path: "/users/my_profile/api_keys", Tag: "api keys", Summary: "List active API keys", Auth: true, Response: []ApiKeyResponse{}},
+	{Method: http.MethodPost, Path: "/users/my_profile/api_keys", Tag: "api keys", Summary: "Create an API key", Description: "The key is only returned once.", Auth: true, Request: CreateApiKeyRequest{}, Status: http.StatusCreated, Response: CreateApiKeyResponse{}},
+	{Method: http.MethodDelete, Path: "/users/my_profile/api_keys/:id", Tag: "api keys", Summary: "Revoke an API key", Auth: true, Request: UserId{}, Response: "", Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodPost, Path: "/users/my_profile/export", Tag: "exports", Summary: "Request an export of the account data", Description: "Returns the pending export when one is already being built.", Auth: true, Status: http.StatusAccepted, Response: ExportResponse{}, Errors: []int{http.StatusServiceUnavailable}},
+	{Method: http.MethodGet, Path: "/users/my_profile/export/:id", Tag: "exports", Summary: "Get the status of an export", Auth: true, Request: ExportId{}, Response: ExportResponse{}, Errors: []int{http.StatusNotFound}},
+	{Method: http.MethodGet, Path: "/users/my_profile/export/:id/download", Tag: "exports", Summary: "Download a ready export", Auth: true, Request: ExportId{}, Response: []byte{}, ContentType: "application/zip", Errors: []int{http.StatusNotFound, http.StatusConflict, http.StatusGone}},
+}
@@ -1,13 +1,16 @@
 package user
 
 import (
-	"fmt"
-	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/pkg"
+	"errors"
 	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/service"
 )
 
+var ErrInvalidRefreshToken = apperror.New(apperror.Unauthorized, "invalid_refresh_token", "can't renew the token")
+
 type renewAccessTokenResponse struct {
 	AccessToken          string    `json:"access_token"`
 	AccessTokenExpiresAt time.Time `json:"access_token_expires_at"`
@@ -16,27 +19,23 @@ type renewAccessTokenResponse struct {
 func (r *userRouter) RenewAccessToken(c *fiber.Ctx) error {
 	refreshToken := c.Cookies("refresh_token")
 	if refreshToken == "" {
-		err := fmt.Errorf("can't renew the token")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusUnauthorized).JSON(pkg.ErrorResponse(err))
+		return ErrInvalidRefreshToken
 	}
 
 	refreshPayload, err := r.token.VerifyToken(refreshToken)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusUnauthorized).JSON(pkg.ErrorResponse(err))
+		return ErrInvalidRefreshToken.Wrap(err)
+	}
+	if err := r.users.CheckActive(c.UserContext(), refreshPayload.UserId); err != nil {
+		if errors.Is(err, service.ErrAccountDeleted) {
+			return ErrInvalidRefreshToken.Wrap(err)
+		}
+		return err
 	}
 
 	accessToken, accessPayload, err := r.token.CreateToken(refreshPayload.UserId, refreshPayload.Email, r.config.AccessTokenDuration)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
 	rsp := renewAccessTokenResponse{
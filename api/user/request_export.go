@@ -0,0 +1,44 @@
+package user
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+type ExportResponse struct {
+	Id        string     `json:"id"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+func (r *userRouter) RequestExport(c *fiber.Ctx) error {
+	payload := c.Locals("user")
+	data, ok := payload.(*token.Payload)
+	if !ok {
+		return apperror.ErrUnauthenticated
+	}
+
+	export, err := r.exports.Request(c.UserContext(), data.UserId)
+	if err != nil {
+		return err
+	}
+
+	res := ConvertExport(*export)
+	return c.Status(fiber.StatusAccepted).JSON(res)
+}
+
+func ConvertExport(export entity.DataExport) ExportResponse {
+	return ExportResponse{
+		Id:        export.Id,
+		Status:    export.Status,
+		Error:     export.Error,
+		CreatedAt: export.CreatedAt,
+		ExpiresAt: export.ExpiresAt,
+	}
+}
@@ -0,0 +1,24 @@
+package user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/pkg"
+)
+
+// RestoreProfile undoes DeleteMyProfile while the account is still within
+// the deletion grace period and has not been anonymized. Accounts without a
+// password are restored through OidcRestore.
+func (r *userRouter) RestoreProfile(c *fiber.Ctx) error {
+	var req = &LoginUserRequest{}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
+	}
+
+	user, err := r.users.Restore(c.UserContext(), req.Email, req.Password)
+	if err != nil {
+		return err
+	}
+
+	res := ConvertUser(*user)
+	return c.JSON(res)
+}
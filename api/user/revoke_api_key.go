@@ -0,0 +1,28 @@
+package user
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+func (r *userRouter) RevokeApiKey(c *fiber.Ctx) error {
+	payload := c.Locals("user")
+	data, ok := payload.(*token.Payload)
+	if !ok {
+		return apperror.ErrUnauthenticated
+	}
+
+	var req = &UserId{}
+	if err := pkg.Bind(c, req); err != nil {
+		return err
+	}
+
+	err := r.apiKeys.Revoke(c.UserContext(), data.UserId, req.Id)
+	if err != nil {
+		return err
+	}
+
+	return c.SendString("Api key revoked")
+}
@@ -4,33 +4,62 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/metrics"
 	"github.com/zura-t/bookstore_fiber/middlewares/auth"
+	"github.com/zura-t/bookstore_fiber/oidc"
+	"github.com/zura-t/bookstore_fiber/service"
 	"github.com/zura-t/bookstore_fiber/token"
-	_ "gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
 type userRouter struct {
-	log    *logrus.Logger
-	config config.Config
-	db     *gorm.DB
-	token  *token.JwtMaker
+	log        *logrus.Logger
+	config     config.Config
+	users      *service.UserService
+	apiKeys    *service.ApiKeyService
+	identities *service.IdentityService
+	exports    *service.ExportService
+	token      *token.JwtMaker
+	providers  map[string]*oidc.Provider
+	metrics    *metrics.Metrics
 }
 
-func NewuserRouter(app *fiber.App, log *logrus.Logger, config config.Config, db *gorm.DB, token *token.JwtMaker) {
-	r := &userRouter{log, config, db, token}
+func NewuserRouter(app fiber.Router, log *logrus.Logger, config config.Config, services service.Services, maker *token.JwtMaker, metrics *metrics.Metrics) {
+	providers := make(map[string]*oidc.Provider, len(config.OidcProviders))
+	for _, p := range config.OidcProviders {
+		providers[p.Name] = oidc.NewProvider(oidc.ProviderConfig{
+			Name:         p.Name,
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  p.RedirectURL,
+			Scopes:       p.Scopes,
+		}, nil)
+	}
+
+	r := &userRouter{log, config, services.Users, services.ApiKeys, services.Identities, services.Exports, maker, providers, metrics}
 
 	app.Post("/register", r.Register)
 	app.Post("/login", r.Login)
 	app.Post("/renew_token", r.RenewAccessToken)
 	app.Post("/logout", r.Logout)
+	app.Post("/users/restore", r.RestoreProfile)
+	app.Get("/auth/:provider/login", r.OidcLogin)
+	app.Get("/auth/:provider/restore", r.OidcRestore)
+	app.Get("/auth/:provider/callback", r.OidcCallback)
 
-	authRoutes := app.Group("/", auth.New(log, token))
+	session := auth.New(log, maker, services)
 
-	authRoutes.Get("/users", r.GetUsers)
-	authRoutes.Get("/users/my_profile", r.GetMyProfile)
-	authRoutes.Get("/users/:id", r.GetUser)
-	authRoutes.Patch("/users/my_profile", r.UpdateMyProfile)
-	authRoutes.Delete("/users/my_profile", r.DeleteMyProfile)
-	authRoutes.Patch("/users/author", r.BecomeAuthor)
+	app.Get("/users", session, r.GetUsers)
+	app.Get("/users/my_profile", session, r.GetMyProfile)
+	app.Get("/users/my_profile/api_keys", session, r.GetApiKeys)
+	app.Post("/users/my_profile/api_keys", session, r.CreateApiKey)
+	app.Delete("/users/my_profile/api_keys/:id", session, r.RevokeApiKey)
+	app.Post("/users/my_profile/export", session, r.RequestExport)
+	app.Get("/users/my_profile/export/:id", session, r.GetExport)
+	app.Get("/users/my_profile/export/:id/download", session, r.DownloadExport)
+	app.Get("/users/:id", session, r.GetUser)
+	app.Patch("/users/my_profile", session, r.UpdateMyProfile)
+	app.Patch("/users/my_profile/password", session, r.ChangePassword)
+	app.Delete("/users/my_profile", session, r.DeleteMyProfile)
+	app.Patch("/users/author", session, r.BecomeAuthor)
 }
@@ -0,0 +1,323 @@
+package user_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/apitest"
+	"github.com/zura-t/bookstore_fiber/api/user"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/oidc/oidctest"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+func TestUserRoutes(t *testing.T) {
+	h := apitest.New(t)
+	ann := h.CreateUser()
+	bob := h.CreateUser()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   interface{}
+		token  string
+		status int
+	}{
+		{"register", http.MethodPost, "/register", map[string]string{"name": "Eve", "email": pkg.RandomEmail(), "password": apitest.Password}, "", http.StatusOK},
+		{"register invalid email", http.MethodPost, "/register", map[string]string{"name": "Eve", "email": "eve", "password": apitest.Password}, "", http.StatusBadRequest},
+		{"register short password", http.MethodPost, "/register", map[string]string{"name": "Eve", "email": pkg.RandomEmail(), "password": "short"}, "", http.StatusBadRequest},
+		{"register taken email", http.MethodPost, "/register", map[string]string{"name": "Eve", "email": ann.Email, "password": apitest.Password}, "", http.StatusConflict},
+		{"login", http.MethodPost, "/login", map[string]string{"email": ann.Email, "password": apitest.Password}, "", http.StatusOK},
+		{"login wrong password", http.MethodPost, "/login", map[string]string{"email": ann.Email, "password": "wrong password"}, "", http.StatusUnauthorized},
+		{"login unknown email", http.MethodPost, "/login", map[string]string{"email": pkg.RandomEmail(), "password": apitest.Password}, "", http.StatusNotFound},
+		{"renew without cookie", http.MethodPost, "/renew_token", nil, "", http.StatusUnauthorized},
+		{"logout", http.MethodPost, "/logout", nil, "", http.StatusOK},
+		{"unknown oidc provider login", http.MethodGet, "/auth/unknown/login", nil, "", http.StatusNotFound},
+		{"unknown oidc provider callback", http.MethodGet, "/auth/unknown/callback", nil, "", http.StatusNotFound},
+		{"users without token", http.MethodGet, "/users", nil, "", http.StatusUnauthorized},
+		{"users", http.MethodGet, "/users", nil, ann.Token, http.StatusOK},
+		{"user", http.MethodGet, fmt.Sprintf("/users/%d", bob.Id), nil, ann.Token, http.StatusOK},
+		{"unknown user", http.MethodGet, "/users/9999", nil, ann.Token, http.StatusNotFound},
+		{"my profile", http.MethodGet, "/users/my_profile", nil, ann.Token, http.StatusOK},
+		{"my profile invalid token", http.MethodGet, "/users/my_profile", nil, "invalid", http.StatusUnauthorized},
+		{"update my profile", http.MethodPatch, "/users/my_profile", map[string]string{"name": "Ann"}, ann.Token, http.StatusOK},
+		{"update my profile empty name", http.MethodPatch, "/users/my_profile", map[string]string{"name": ""}, ann.Token, http.StatusBadRequest},
+		{"update my profile locale", http.MethodPatch, "/users/my_profile", map[string]string{"name": "Ann", "locale": "ru"}, ann.Token, http.StatusOK},
+		{"update my profile unsupported locale", http.MethodPatch, "/users/my_profile", map[string]string{"name": "Ann", "locale": "xx"}, ann.Token, http.StatusBadRequest},
+		{"change password wrong old", http.MethodPatch, "/users/my_profile/password", map[string]string{"old_password": "wrong password", "new_password": "another password"}, bob.Token, http.StatusUnauthorized},
+		{"change password", http.MethodPatch, "/users/my_profile/password", map[string]string{"old_password": apitest.Password, "new_password": "another password"}, bob.Token, http.StatusOK},
+		{"login old password", http.MethodPost, "/login", map[string]string{"email": bob.Email, "password": apitest.Password}, "", http.StatusUnauthorized},
+		{"become author", http.MethodPatch, "/users/author", nil, ann.Token, http.StatusOK},
+		{"api keys", http.MethodGet, "/users/my_profile/api_keys", nil, ann.Token, http.StatusOK},
+		{"create api key unknown scope", http.MethodPost, "/users/my_profile/api_keys", map[string]interface{}{"name": "ci", "scopes": []string{"admin"}}, ann.Token, http.StatusBadRequest},
+		{"revoke unknown api key", http.MethodDelete, "/users/my_profile/api_keys/9999", nil, ann.Token, http.StatusNotFound},
+		{"invalid export id", http.MethodGet, "/users/my_profile/export/1", nil, ann.Token, http.StatusBadRequest},
+		{"unknown export", http.MethodGet, "/users/my_profile/export/9f0c1a4e-6a8b-4c39-9a7e-1d2b3c4d5e6f", nil, ann.Token, http.StatusNotFound},
+		{"restore active account", http.MethodPost, "/users/restore", map[string]string{"email": ann.Email, "password": apitest.Password}, "", http.StatusNotFound},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := h.Request(tc.method, tc.path, tc.body, tc.token)
+			apitest.Decode(t, resp, tc.status, nil)
+		})
+	}
+}
+
+func TestRenewToken(t *testing.T) {
+	h := apitest.New(t)
+	ann := h.CreateUser()
+
+	var session user.LoginUserResponse
+	resp := h.Request(http.MethodPost, "/login", map[string]string{"email": ann.Email, "password": ann.Password}, "")
+	apitest.Decode(t, resp, http.StatusOK, &session)
+	require.Equal(t, ann.Id, session.User.Id)
+
+	req := httptest.NewRequest(http.MethodPost, "/renew_token", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: session.RefreshToken})
+	var renewed struct {
+		AccessToken string `json:"access_token"`
+	}
+	apitest.Decode(t, h.Do(req), http.StatusOK, &renewed)
+
+	apitest.Decode(t, h.Request(http.MethodGet, "/users/my_profile", nil, renewed.AccessToken), http.StatusOK, nil)
+}
+
+func TestApiKeys(t *testing.T) {
+	h := apitest.New(t)
+	ann := h.CreateUser()
+
+	var created user.CreateApiKeyResponse
+	resp := h.Request(http.MethodPost, "/users/my_profile/api_keys", map[string]interface{}{
+		"name":   "ci",
+		"scopes": []string{"catalog:read"},
+	}, ann.Token)
+	apitest.Decode(t, resp, http.StatusCreated, &created)
+	require.NotEmpty(t, created.Key)
+
+	var keys []user.ApiKeyResponse
+	apitest.Decode(t, h.Request(http.MethodGet, "/users/my_profile/api_keys", nil, ann.Token), http.StatusOK, &keys)
+	require.Len(t, keys, 1)
+	require.Equal(t, created.Prefix, keys[0].Prefix)
+
+	apitest.Decode(t, h.Request(http.MethodGet, "/books", nil, created.Key), http.StatusOK, nil)
+	apitest.Decode(t, h.Request(http.MethodGet, "/users", nil, created.Key), http.StatusForbidden, nil)
+	apitest.Decode(t, h.Request(http.MethodGet, "/users/my_profile", nil, created.Key), http.StatusForbidden, nil)
+
+	path := fmt.Sprintf("/users/my_profile/api_keys/%d", created.Id)
+	apitest.Decode(t, h.Request(http.MethodDelete, path, nil, ann.Token), http.StatusOK, nil)
+	apitest.Decode(t, h.Request(http.MethodGet, "/books", nil, created.Key), http.StatusUnauthorized, nil)
+}
+
+func TestDataExport(t *testing.T) {
+	h := apitest.New(t)
+	ann := h.CreateUser()
+
+	var export user.ExportResponse
+	apitest.Decode(t, h.Request(http.MethodPost, "/users/my_profile/export", nil, ann.Token), http.StatusAccepted, &export)
+	require.NotEmpty(t, export.Id)
+
+	path := "/users/my_profile/export/" + export.Id
+	require.Eventually(t, func() bool {
+		var current user.ExportResponse
+		resp := h.Request(http.MethodGet, path, nil, ann.Token)
+		return json.NewDecoder(resp.Body).Decode(&current) == nil && current.Status == "ready"
+	}, 5*time.Second, 10*time.Millisecond)
+
+	resp := h.Request(http.MethodGet, path+"/download", nil, ann.Token)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "application/zip", resp.Header.Get("Content-Type"))
+
+	bob := h.CreateUser()
+	apitest.Decode(t, h.Request(http.MethodGet, path, nil, bob.Token), http.StatusNotFound, nil)
+}
+
+func TestDataExportPending(t *testing.T) {
+	// Without workers exports stay pending.
+	h := apitest.New(t, func(c *config.Config) { c.ExportWorkers = 0 })
+	ann := h.CreateUser()
+
+	var first, second user.ExportResponse
+	apitest.Decode(t, h.Request(http.MethodPost, "/users/my_profile/export", nil, ann.Token), http.StatusAccepted, &first)
+	apitest.Decode(t, h.Request(http.MethodPost, "/users/my_profile/export", nil, ann.Token), http.StatusAccepted, &second)
+	require.Equal(t, "pending", second.Status)
+	require.Equal(t, first.Id, second.Id)
+
+	bob := h.CreateUser()
+	var other user.ExportResponse
+	apitest.Decode(t, h.Request(http.MethodPost, "/users/my_profile/export", nil, bob.Token), http.StatusAccepted, &other)
+	require.NotEqual(t, first.Id, other.Id)
+}
+
+func TestDeleteAndRestoreProfile(t *testing.T) {
+	h := apitest.New(t)
+	ann := h.CreateUser()
+
+	login := map[string]string{"email": ann.Email, "password": ann.Password}
+	var session user.LoginUserResponse
+	apitest.Decode(t, h.Request(http.MethodPost, "/login", login, ""), http.StatusOK, &session)
+
+	apitest.Decode(t, h.Request(http.MethodDelete, "/users/my_profile", nil, ann.Token), http.StatusOK, nil)
+	apitest.Decode(t, h.Request(http.MethodPost, "/login", login, ""), http.StatusNotFound, nil)
+
+	// Sessions end with the deletion.
+	apitest.Decode(t, h.Request(http.MethodGet, "/users/my_profile", nil, ann.Token), http.StatusUnauthorized, nil)
+	apitest.Decode(t, h.Request(http.MethodPost, "/readlist", map[string]uint{"book_id": 1}, ann.Token), http.StatusUnauthorized, nil)
+	req := httptest.NewRequest(http.MethodPost, "/renew_token", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: session.RefreshToken})
+	apitest.Decode(t, h.Do(req), http.StatusUnauthorized, nil)
+
+	wrong := map[string]string{"email": ann.Email, "password": "wrong password"}
+	apitest.Decode(t, h.Request(http.MethodPost, "/users/restore", wrong, ""), http.StatusUnauthorized, nil)
+
+	var restored user.UserResponse
+	apitest.Decode(t, h.Request(http.MethodPost, "/users/restore", login, ""), http.StatusOK, &restored)
+	require.Equal(t, ann.Id, restored.Id)
+
+	apitest.Decode(t, h.Request(http.MethodPost, "/login", login, ""), http.StatusOK, nil)
+}
+
+func TestOidcLogin(t *testing.T) {
+	mock := oidctest.NewProvider()
+	defer mock.Close()
+
+	h := apitest.New(t, func(c *config.Config) {
+		c.OidcProviders = []config.OidcProvider{{
+			Name:        "mock",
+			Issuer:      mock.Issuer(),
+			ClientID:    "bookstore",
+			RedirectURL: "http://localhost/auth/mock/callback",
+		}}
+	})
+	ann := h.CreateUser()
+	mock.SetUser(oidctest.User{Subject: pkg.RandomString(12), Email: ann.Email, EmailVerified: true})
+
+	callback := authorize(t, h, "/auth/mock/login")
+	var session user.LoginUserResponse
+	apitest.Decode(t, h.Request(http.MethodGet, callback, nil, ""), http.StatusOK, &session)
+	require.Equal(t, ann.Id, session.User.Id)
+
+	apitest.Decode(t, h.Request(http.MethodGet, callback, nil, ""), http.StatusBadRequest, nil)
+}
+
+// authorize follows an OIDC login started at path through the provider and
+// returns the callback to complete it with.
+func authorize(t *testing.T, h *apitest.Harness, path string) string {
+	t.Helper()
+
+	resp := h.Request(http.MethodGet, path, nil, "")
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	authorized, err := client.Get(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	defer authorized.Body.Close()
+	require.Equal(t, http.StatusFound, authorized.StatusCode)
+
+	callback, err := url.Parse(authorized.Header.Get("Location"))
+	require.NoError(t, err)
+	return callback.RequestURI()
+}
+
+func TestOidcRestore(t *testing.T) {
+	mock := oidctest.NewProvider()
+	defer mock.Close()
+
+	h := apitest.New(t, func(c *config.Config) {
+		c.OidcProviders = []config.OidcProvider{{
+			Name:        "mock",
+			Issuer:      mock.Issuer(),
+			ClientID:    "bookstore",
+			RedirectURL: "http://localhost/auth/mock/callback",
+		}}
+	})
+	mock.SetUser(oidctest.User{Subject: pkg.RandomString(12), Email: pkg.RandomEmail(), EmailVerified: true})
+
+	// The account is created by the login and has no password.
+	var session user.LoginUserResponse
+	apitest.Decode(t, h.Request(http.MethodGet, authorize(t, h, "/auth/mock/login"), nil, ""), http.StatusOK, &session)
+	apitest.Decode(t, h.Request(http.MethodDelete, "/users/my_profile", nil, session.AccessToken), http.StatusOK, nil)
+
+	apitest.Decode(t, h.Request(http.MethodGet, authorize(t, h, "/auth/mock/login"), nil, ""), http.StatusForbidden, nil)
+
+	var restored user.LoginUserResponse
+	apitest.Decode(t, h.Request(http.MethodGet, authorize(t, h, "/auth/mock/restore"), nil, ""), http.StatusOK, &restored)
+	require.Equal(t, session.User.Id, restored.User.Id)
+	apitest.Decode(t, h.Request(http.MethodGet, "/users/my_profile", nil, restored.AccessToken), http.StatusOK, nil)
+
+	// Active accounts have nothing to restore.
+	apitest.Decode(t, h.Request(http.MethodGet, authorize(t, h, "/auth/mock/restore"), nil, ""), http.StatusNotFound, nil)
+}
+
+func TestLoginRateLimit(t *testing.T) {
+	h := apitest.New(t, func(c *config.Config) {
+		c.RateLimitStore = "database"
+		c.RateLimitDefault = config.Rate{Limit: 100, Period: time.Minute}
+		c.RateLimitRoutes = []config.RouteRate{{Method: http.MethodPost, Path: "/login", Rate: config.Rate{Limit: 2, Period: time.Minute}}}
+	})
+	ann := h.CreateUser()
+
+	// CreateUser took the first token.
+	login := map[string]string{"email": ann.Email, "password": ann.Password}
+	apitest.Decode(t, h.Request(http.MethodPost, "/login", login, ""), http.StatusOK, nil)
+	resp := h.Request(http.MethodPost, "/login", login, "")
+	require.NotEmpty(t, resp.Header.Get("Retry-After"))
+	apitest.Decode(t, resp, http.StatusTooManyRequests, nil)
+
+	apitest.Decode(t, h.Request(http.MethodGet, "/users/my_profile", nil, ann.Token), http.StatusOK, nil)
+}
+
+func TestRateLimitBehindProxy(t *testing.T) {
+	h := apitest.New(t, func(c *config.Config) {
+		c.RateLimitStore = "memory"
+		c.ProxyHeader = fiber.HeaderXForwardedFor
+		c.TrustedProxies = []string{"0.0.0.0"}
+		c.RateLimitDefault = config.Rate{Limit: 100, Period: time.Minute}
+		c.RateLimitRoutes = []config.RouteRate{{Method: http.MethodPost, Path: "/login", Rate: config.Rate{Limit: 1, Period: time.Minute}}}
+	})
+
+	login := func(ip string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email": "eve@example.com", "password": "long enough password"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set(fiber.HeaderXForwardedFor, ip+", 10.0.0.1")
+		return h.Do(req)
+	}
+
+	// Clients behind the proxy get a bucket each.
+	apitest.Decode(t, login("203.0.113.1"), http.StatusNotFound, nil)
+	apitest.Decode(t, login("203.0.113.2"), http.StatusNotFound, nil)
+	apitest.Decode(t, login("203.0.113.1"), http.StatusTooManyRequests, nil)
+}
+
+func TestRateLimitIgnoresUnknownApiKeys(t *testing.T) {
+	h := apitest.New(t, func(c *config.Config) {
+		c.RateLimitStore = "memory"
+		c.RateLimitDefault = config.Rate{Limit: 100, Period: time.Minute}
+		c.RateLimitRoutes = []config.RouteRate{{Method: http.MethodPost, Path: "/login", Rate: config.Rate{Limit: 2, Period: time.Minute}}}
+	})
+
+	// Made up keys don't get buckets of their own, the requests are counted
+	// per IP.
+	status := make([]int, 3)
+	for i := range status {
+		apiKey, _, _, err := token.GenerateApiKey()
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(`{"email": "eve@example.com", "password": "long enough password"}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		req.Header.Set("X-API-Key", apiKey)
+		status[i] = h.Do(req).StatusCode
+	}
+	require.Equal(t, []int{http.StatusNotFound, http.StatusNotFound, http.StatusTooManyRequests}, status)
+}
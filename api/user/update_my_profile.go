@@ -1,64 +1,34 @@
 package user
 
 import (
-	"fmt"
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
-	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/apperror"
 	"github.com/zura-t/bookstore_fiber/pkg"
 	"github.com/zura-t/bookstore_fiber/token"
-	"gorm.io/gorm/clause"
 )
 
 type UserUpdate struct {
-	Name string `json:"name" validate:"required,min=1"`
+	Name   string `json:"name" validate:"required,min=1"`
+	Locale string `json:"locale" validate:"omitempty,locale"`
 }
 
 func (r *userRouter) UpdateMyProfile(c *fiber.Ctx) error {
 	payload := c.Locals("user")
 	data, ok := payload.(*token.Payload)
 	if !ok {
-		err := fmt.Errorf("Can't get payload")
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
+		return apperror.ErrUnauthenticated
 	}
 
 	var user = &UserUpdate{}
-	if err := c.BodyParser(user); err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusServiceUnavailable).JSON(pkg.ErrorResponse(err))
+	if err := pkg.Bind(c, user); err != nil {
+		return err
 	}
 
-	validate := validator.New()
-	if err := validate.Struct(user); err != nil {
-		validationErrors, ok := err.(validator.ValidationErrors)
-		if ok {
-			validation_errs := pkg.ListValidationErrors(user, validationErrors)
-			r.log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(validation_errs)
-			return c.Status(fiber.StatusBadRequest).JSON(pkg.MultipleErrorsResponse(validation_errs))
-		}
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusBadRequest).JSON(pkg.ErrorResponse(err))
-	}
-
-	var res models.User
-	err := r.db.Model(&res).Clauses(clause.Returning{}).Where("id = ?", data.UserId).Updates(&user).Error
+	res, err := r.users.UpdateProfile(c.UserContext(), data.UserId, user.Name, user.Locale)
 	if err != nil {
-		r.log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
-		return c.Status(fiber.StatusInternalServerError).JSON(pkg.ErrorResponse(err))
+		return err
 	}
 
-	resp := ConvertUser(res)
+	resp := ConvertUser(*res)
 	return c.JSON(resp)
 }
@@ -0,0 +1,47 @@
+package api
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/api/book"
+	"github.com/zura-t/bookstore_fiber/api/cart"
+	"github.com/zura-t/bookstore_fiber/api/user"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/metrics"
+	"github.com/zura-t/bookstore_fiber/middlewares/ratelimit"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+// versionRoutes holds what the routers of every API version depend on. Each
+// version has a mount method registering its routers on its group, so that a
+// version changing response shapes gets routers of its own while the older
+// versions keep theirs.
+type versionRoutes struct {
+	log      *logrus.Logger
+	config   config.Config
+	services service.Services
+	maker    *token.JwtMaker
+	metrics  *metrics.Metrics
+	// limiter is nil when rate limiting is off.
+	limiter *ratelimit.Limiter
+}
+
+func (v versionRoutes) mountV1(router fiber.Router) {
+	v.routeRateLimits(router)
+	user.NewuserRouter(router, v.log, v.config, v.services, v.maker, v.metrics)
+	book.NewBookRouter(router, v.log, v.config, v.services, v.maker, v.metrics)
+	cart.NewCartRouter(router, v.log, v.config, v.services, v.maker)
+}
+
+// routeRateLimits applies the route rates on top of the default one. They run
+// after it, so their headers are the ones clients see. The policy name
+// doesn't include the version, a route shares its buckets across versions.
+func (v versionRoutes) routeRateLimits(router fiber.Router) {
+	if v.limiter == nil {
+		return
+	}
+	for _, route := range v.config.RateLimitRoutes {
+		router.Add(route.Method, route.Path, v.limiter.Handler(route.Method+" "+route.Path, route.Rate))
+	}
+}
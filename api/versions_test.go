@@ -0,0 +1,38 @@
+package api_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/apitest"
+	"github.com/zura-t/bookstore_fiber/middlewares/deprecation"
+)
+
+func TestVersionedRoutes(t *testing.T) {
+	h := apitest.New(t)
+	ann := h.CreateUser()
+
+	resp := h.Request(http.MethodGet, "/v1/users/my_profile", nil, ann.Token)
+	require.Empty(t, resp.Header.Get(deprecation.HeaderDeprecation))
+	apitest.Decode(t, resp, http.StatusOK, nil)
+
+	resp = h.Request(http.MethodGet, "/books?limit=5", nil, "")
+	require.Equal(t, "@1792368000", resp.Header.Get(deprecation.HeaderDeprecation))
+	require.Equal(t, "Fri, 30 Apr 2027 00:00:00 GMT", resp.Header.Get(deprecation.HeaderSunset))
+	require.Equal(t, `</v1/books?limit=5>; rel="successor-version"`, resp.Header.Get(fiber.HeaderLink))
+	apitest.Decode(t, resp, http.StatusOK, nil)
+
+	// Errors of deprecated routes are marked too, unknown routes are not.
+	resp = h.Request(http.MethodGet, "/users/my_profile", nil, "")
+	require.NotEmpty(t, resp.Header.Get(deprecation.HeaderDeprecation))
+	apitest.Decode(t, resp, http.StatusUnauthorized, nil)
+
+	resp = h.Request(http.MethodGet, "/unknown", nil, "")
+	require.Empty(t, resp.Header.Get(deprecation.HeaderDeprecation))
+	apitest.Decode(t, resp, http.StatusNotFound, nil)
+	resp = h.Request(http.MethodGet, "/v1/unknown", nil, "")
+	require.Empty(t, resp.Header.Get(deprecation.HeaderDeprecation))
+	apitest.Decode(t, resp, http.StatusNotFound, nil)
+}
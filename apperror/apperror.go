@@ -0,0 +1,102 @@
+// Package apperror defines the errors reported to clients. Every error has a
+// kind, which decides the HTTP status, and a stable code clients can match
+// on instead of the message.
+package apperror
+
+type Kind int
+
+const (
+	Internal Kind = iota
+	Invalid
+	Unauthorized
+	Forbidden
+	NotFound
+	Conflict
+	Gone
+	TooManyRequests
+	Unavailable
+	BadGateway
+)
+
+// FieldError describes why one request field is invalid. Key and Params
+// identify the message in the i18n catalogs, Message is its English text.
+type FieldError struct {
+	Field   string   `json:"field"`
+	Message string   `json:"message"`
+	Key     string   `json:"-"`
+	Params  []string `json:"-"`
+}
+
+// Error is an error reported to clients. Message is the English text, the
+// catalogs translate it by Code and fill in Params.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	Params  []string
+	Fields  []FieldError
+	cause   error
+}
+
+func New(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+// Error includes the cause, which is logged but never sent to clients.
+func (e *Error) Error() string {
+	if e.cause != nil {
+		return e.Message + ": " + e.cause.Error()
+	}
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is matches errors with the same code, so that errors.Is finds a sentinel
+// after Wrap or WithFields made a copy of it.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Wrap returns a copy of e caused by cause.
+func (e *Error) Wrap(cause error) *Error {
+	res := *e
+	res.cause = cause
+	return &res
+}
+
+// WithFields returns a copy of e listing the invalid fields.
+func (e *Error) WithFields(fields []FieldError) *Error {
+	res := *e
+	res.Fields = fields
+	return &res
+}
+
+// WithMessage returns a copy of e with a more specific message. Clients
+// get the catalog text of the code, so a message that says more than it
+// needs its own code or WithParams.
+func (e *Error) WithMessage(message string) *Error {
+	res := *e
+	res.Message = message
+	return &res
+}
+
+// WithParams returns a copy of e with the values of the placeholders in the
+// catalog text of its code.
+func (e *Error) WithParams(params ...string) *Error {
+	res := *e
+	res.Params = params
+	return &res
+}
+
+var (
+	ErrInternal        = New(Internal, "internal_error", "Internal server error")
+	ErrInvalidBody     = New(Invalid, "invalid_body", "Request body is invalid")
+	ErrInvalidParams   = New(Invalid, "invalid_params", "Request parameters are invalid")
+	ErrValidation      = New(Invalid, "validation_failed", "Request validation failed")
+	ErrUnauthenticated = New(Unauthorized, "unauthenticated", "Authentication required")
+	ErrForbidden       = New(Forbidden, "forbidden", "Forbidden")
+)
@@ -1,49 +1,175 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
 	"github.com/zura-t/bookstore_fiber/api"
 	"github.com/zura-t/bookstore_fiber/config"
 	"github.com/zura-t/bookstore_fiber/database"
+	"github.com/zura-t/bookstore_fiber/jobs"
 	"github.com/zura-t/bookstore_fiber/logger"
-	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/migrations"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/rpc"
+	"github.com/zura-t/bookstore_fiber/tracing"
+	"google.golang.org/grpc"
 )
 
 func main() {
-	config, err := config.LoadConfig(".")
+	flags := config.Flags(os.Args[0])
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, pflag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	args := flags.Args()
+
+	config, err := config.LoadConfig(".", flags)
 	if err != nil {
-		fmt.Errorf("can't load config file:", err)
+		fmt.Fprintln(os.Stderr, "can't load config:", err)
+		os.Exit(1)
+	}
+
+	if len(args) > 0 && args[0] == "config" {
+		if err := config.Dump(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
 	}
-	app := fiber.New()
-	log := logger.SetupLogger(config.Environment)
 
-	db, err := database.Connect(config)
+	app := api.NewApp(config)
+	log := logger.SetupLogger(config.Environment, config.LogLevel)
+
+	cluster, err := database.Connect(config)
 	if err != nil {
-		log.WithFields(logrus.Fields{
-			"level": "Panic",
-		}).Panic(err)
+		log.Panic(err)
 		panic("failed to connect database")
 	}
 
-	log.WithFields(logrus.Fields{
-		"level": "Info",
-	}).Info("Connection opened to database")
+	log.Info("Connection opened to database")
+	db := cluster.Primary
+	defer func() {
+		if err := cluster.Close(); err != nil {
+			log.Error(err)
+		}
+	}()
+
+	if len(args) > 0 && args[0] == "migrate" {
+		if err := runMigrate(db, args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	db.AutoMigrate(&models.User{}, &models.Book{}, &models.UserBook{}, &models.CartItem{})
+	migrator, err := migrations.New(db)
+	if err == nil {
+		err = migrator.Check(context.Background())
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Options{
+		Exporter:    config.TracingExporter,
+		Endpoint:    config.TracingEndpoint,
+		File:        config.TracingFile,
+		SampleRatio: config.TracingSampleRatio,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Error(err)
+		}
+	}()
 
-	log.WithFields(logrus.Fields{
-		"level": "Info",
-	}).Info("Migrated")
+	purger, err := jobs.NewAccountPurger(log, db, config)
+	if err != nil {
+		log.Fatal(err)
+	}
+	go purger.Run(ctx)
 
-	// app.Use(middleware.Logger())
+	exporter := jobs.NewDataExporter(log, db, config)
+	go exporter.Run(ctx)
 
 	app.Use(cors.New())
 
-	api.NewRouter(app, log, config, db)
+	repos := repository.NewGormClusterRepositories(db, cluster.Reader)
+	deps, err := api.NewDependencies(log, config, cluster, repos, exporter)
+	if err != nil {
+		log.Fatal(err)
+	}
+	api.NewRouter(app, log, config, cluster, deps)
+
+	listenErr := make(chan error, 2)
+	go func() {
+		listenErr <- app.Listen(config.HttpAddress())
+	}()
+
+	var grpcServer *grpc.Server
+	if config.UsersServiceAddress != "" {
+		listener, err := net.Listen("tcp", config.UsersServiceAddress)
+		if err != nil {
+			log.Fatal(err)
+		}
+		grpcServer = rpc.NewServer(log, config.UsersServiceToken, deps.Services, deps.Maker, deps.Checks)
+		go func() {
+			listenErr <- grpcServer.Serve(listener)
+		}()
+		log.Infof("gRPC server listening on %s", config.UsersServiceAddress)
+	}
+
+	select {
+	case err := <-listenErr:
+		log.Fatal(err)
+	case <-ctx.Done():
+	}
+
+	// In-flight requests and calls get ShutdownTimeout to finish, the
+	// database is closed by the deferred cluster.Close once they are done.
+	log.Info("Shutting down")
+	grpcStopped := make(chan struct{})
+	go func() {
+		if grpcServer != nil {
+			stopGracefully(grpcServer, config.ShutdownTimeout)
+		}
+		close(grpcStopped)
+	}()
+	if err := app.ShutdownWithTimeout(config.ShutdownTimeout); err != nil {
+		log.Error(err)
+	}
+	<-grpcStopped
+
+	// Queued exports get another ShutdownTimeout to be built.
+	exportCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := exporter.Shutdown(exportCtx); err != nil {
+		log.Error(err)
+	}
+}
 
-	app.Listen("127.0.0.1:8080")
+// stopGracefully waits for in-flight calls for at most timeout, then cancels
+// them.
+func stopGracefully(server *grpc.Server, timeout time.Duration) {
+	timer := time.AfterFunc(timeout, server.Stop)
+	defer timer.Stop()
+	server.GracefulStop()
 }
@@ -1,34 +1,339 @@
 package config
 
 import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
 )
 
 type Config struct {
-	HttpPort             string        `mapstructure:"HTTP_PORT"`
-	UsersServiceAddress  string        `mapstructure:"USERS_SERVICE_ADDRESS"`
-	DbUrl                string        `mapstructure:"DB_URL"`
-	TokenKey             string        `mapstructure:"TOKEN_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
-	LogLevel             string        `mapstructure:"LOG_LEVEL"`
-	Environment          string        `mapstructure:"ENVIRONMENT"`
+	HttpHost              string         `mapstructure:"HTTP_HOST"`
+	HttpPort              string         `mapstructure:"HTTP_PORT"`
+	ShutdownTimeout       time.Duration  `mapstructure:"SHUTDOWN_TIMEOUT"`
+	MetricsToken          string         `mapstructure:"METRICS_TOKEN" secret:"true"`
+	TracingExporter       string         `mapstructure:"TRACING_EXPORTER"`
+	TracingEndpoint       string         `mapstructure:"TRACING_ENDPOINT"`
+	TracingFile           string         `mapstructure:"TRACING_FILE"`
+	TracingSampleRatio    float64        `mapstructure:"TRACING_SAMPLE_RATIO"`
+	UsersServiceAddress   string         `mapstructure:"USERS_SERVICE_ADDRESS"`
+	UsersServiceToken     string         `mapstructure:"USERS_SERVICE_TOKEN" secret:"true"`
+	DbDriver              string         `mapstructure:"DB_DRIVER"`
+	DbUrl                 string         `mapstructure:"DB_URL" secret:"true"`
+	DbReplicaUrlList      string         `mapstructure:"DB_REPLICA_URLS" secret:"true"`
+	DbReplicaUrls         []string       `mapstructure:"-"`
+	DbMaxOpenConns        int            `mapstructure:"DB_MAX_OPEN_CONNS"`
+	DbMaxIdleConns        int            `mapstructure:"DB_MAX_IDLE_CONNS"`
+	DbConnMaxLifetime     time.Duration  `mapstructure:"DB_CONN_MAX_LIFETIME"`
+	DbConnMaxIdleTime     time.Duration  `mapstructure:"DB_CONN_MAX_IDLE_TIME"`
+	DbStatementTimeout    time.Duration  `mapstructure:"DB_STATEMENT_TIMEOUT"`
+	TokenKey              string         `mapstructure:"TOKEN_KEY" secret:"true"`
+	AccessTokenDuration   time.Duration  `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration  time.Duration  `mapstructure:"REFRESH_TOKEN_DURATION"`
+	LogLevel              string         `mapstructure:"LOG_LEVEL"`
+	Environment           string         `mapstructure:"ENVIRONMENT"`
+	Argon2Memory          uint32         `mapstructure:"ARGON2_MEMORY"`
+	Argon2Iterations      uint32         `mapstructure:"ARGON2_ITERATIONS"`
+	Argon2Parallelism     uint8          `mapstructure:"ARGON2_PARALLELISM"`
+	PasswordMinLength     int            `mapstructure:"PASSWORD_MIN_LENGTH"`
+	PasswordMaxLength     int            `mapstructure:"PASSWORD_MAX_LENGTH"`
+	BreachedPasswordsFile string         `mapstructure:"BREACHED_PASSWORDS_FILE"`
+	OidcProviderNames     string         `mapstructure:"OIDC_PROVIDERS"`
+	OidcStateDuration     time.Duration  `mapstructure:"OIDC_STATE_DURATION"`
+	OidcProviders         []OidcProvider `mapstructure:"-"`
+	DeletionGracePeriod   time.Duration  `mapstructure:"ACCOUNT_DELETION_GRACE_PERIOD"`
+	PurgeInterval         time.Duration  `mapstructure:"ACCOUNT_PURGE_INTERVAL"`
+	DeletedAuthorBooks    string         `mapstructure:"DELETED_AUTHOR_BOOKS_POLICY"`
+	DeletedAuthorReassign uint           `mapstructure:"DELETED_AUTHOR_BOOKS_REASSIGN_TO"`
+	ExportDir             string         `mapstructure:"EXPORT_DIR"`
+	ExportTTL             time.Duration  `mapstructure:"EXPORT_TTL"`
+	ExportWorkers         int            `mapstructure:"EXPORT_WORKERS"`
+	RateLimitStore        string         `mapstructure:"RATE_LIMIT_STORE"`
+	RateLimitDefaultRate  string         `mapstructure:"RATE_LIMIT_DEFAULT"`
+	RateLimitRouteList    string         `mapstructure:"RATE_LIMIT_ROUTES"`
+	RateLimitDefault      Rate           `mapstructure:"-"`
+	RateLimitRoutes       []RouteRate    `mapstructure:"-"`
+	ProxyHeader           string         `mapstructure:"PROXY_HEADER"`
+	TrustedProxyList      string         `mapstructure:"TRUSTED_PROXIES"`
+	TrustedProxies        []string       `mapstructure:"-"`
+	LegacyDeprecationDate string         `mapstructure:"LEGACY_ROUTES_DEPRECATED_AT"`
+	LegacySunsetDate      string         `mapstructure:"LEGACY_ROUTES_SUNSET"`
+	LegacyDeprecatedAt    time.Time      `mapstructure:"-"`
+	LegacySunset          time.Time      `mapstructure:"-"`
+	GraphQLMaxDepth       int            `mapstructure:"GRAPHQL_MAX_DEPTH"`
+	GraphQLMaxComplexity  int            `mapstructure:"GRAPHQL_MAX_COMPLEXITY"`
 }
 
-func LoadConfig(path string) (config Config, err error) {
-	viper.AddConfigPath(path)
-	viper.SetConfigName("app")
-	viper.SetConfigType("env")
+// OidcProvider is read from OIDC_<NAME>_* variables for every name listed in
+// OIDC_PROVIDERS, e.g. OIDC_PROVIDERS=google with OIDC_GOOGLE_ISSUER.
+type OidcProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Rate is a token bucket holding Limit requests that refills Limit tokens
+// every Period. It is written as "limit/period", e.g. "10/1m".
+type Rate struct {
+	Limit  int
+	Period time.Duration
+}
+
+func ParseRate(s string) (Rate, error) {
+	limit, period, ok := strings.Cut(strings.TrimSpace(s), "/")
+	if !ok {
+		return Rate{}, fmt.Errorf("rate %q must be written as limit/period, e.g. 10/1m", s)
+	}
+	var rate Rate
+	var err error
+	if rate.Limit, err = strconv.Atoi(limit); err != nil || rate.Limit < 1 {
+		return Rate{}, fmt.Errorf("rate %q must have a positive limit", s)
+	}
+	if rate.Period, err = time.ParseDuration(period); err != nil || rate.Period <= 0 {
+		return Rate{}, fmt.Errorf("rate %q must have a positive period", s)
+	}
+	return rate, nil
+}
+
+func (r Rate) String() string {
+	return fmt.Sprintf("%d/%s", r.Limit, r.Period)
+}
+
+// RouteRate is a rate for one route, read from RATE_LIMIT_ROUTES as a comma
+// separated list of "METHOD /path=limit/period". Path is a Fiber route path.
+type RouteRate struct {
+	Method string
+	Path   string
+	Rate   Rate
+}
+
+// Flags returns a flag set with a flag for every setting, named after its
+// variable, e.g. --http-port for HTTP_PORT, and --config to read another
+// env file than app.env.
+func Flags(name string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	flags.String("config", "", "env file to read instead of app.env")
+	for _, key := range keys() {
+		flags.String(flagName(key), "", "overrides "+key)
+	}
+	return flags
+}
+
+// LoadConfig merges, from lowest to highest precedence, the defaults, the
+// app.env file in path, the environment and the flags set in flags, which may
+// be nil. The file is optional unless it was given with --config. The result
+// is validated.
+func LoadConfig(path string, flags *pflag.FlagSet) (config Config, err error) {
+	v := viper.New()
+	v.SetConfigType("env")
+
+	v.SetDefault("HTTP_HOST", "127.0.0.1")
+	v.SetDefault("HTTP_PORT", "8080")
+	v.SetDefault("SHUTDOWN_TIMEOUT", 15*time.Second)
+	v.SetDefault("METRICS_TOKEN", "")
+	v.SetDefault("USERS_SERVICE_ADDRESS", "127.0.0.1:9090")
+	v.SetDefault("USERS_SERVICE_TOKEN", "")
+	v.SetDefault("TRACING_EXPORTER", "none")
+	v.SetDefault("TRACING_ENDPOINT", "")
+	v.SetDefault("TRACING_FILE", "traces.json")
+	v.SetDefault("TRACING_SAMPLE_RATIO", 1.0)
+	v.SetDefault("ENVIRONMENT", "dev")
+	v.SetDefault("LOG_LEVEL", "")
+	v.SetDefault("DB_DRIVER", "postgres")
+	v.SetDefault("DB_REPLICA_URLS", "")
+	v.SetDefault("DB_MAX_OPEN_CONNS", 25)
+	v.SetDefault("DB_MAX_IDLE_CONNS", 10)
+	v.SetDefault("DB_CONN_MAX_LIFETIME", 30*time.Minute)
+	v.SetDefault("DB_CONN_MAX_IDLE_TIME", 5*time.Minute)
+	v.SetDefault("DB_STATEMENT_TIMEOUT", 0)
+	v.SetDefault("ARGON2_MEMORY", 64*1024)
+	v.SetDefault("ARGON2_ITERATIONS", 3)
+	v.SetDefault("ARGON2_PARALLELISM", 2)
+	v.SetDefault("PASSWORD_MIN_LENGTH", 8)
+	v.SetDefault("PASSWORD_MAX_LENGTH", 128)
+	v.SetDefault("BREACHED_PASSWORDS_FILE", "")
+	v.SetDefault("OIDC_PROVIDERS", "")
+	v.SetDefault("OIDC_STATE_DURATION", 10*time.Minute)
+	v.SetDefault("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour)
+	v.SetDefault("ACCOUNT_PURGE_INTERVAL", time.Hour)
+	v.SetDefault("DELETED_AUTHOR_BOOKS_POLICY", "unpublish")
+	v.SetDefault("DELETED_AUTHOR_BOOKS_REASSIGN_TO", 0)
+	v.SetDefault("EXPORT_DIR", "public/exports")
+	v.SetDefault("EXPORT_TTL", 7*24*time.Hour)
+	v.SetDefault("EXPORT_WORKERS", 2)
+	v.SetDefault("RATE_LIMIT_STORE", "memory")
+	v.SetDefault("RATE_LIMIT_DEFAULT", "300/1m")
+	// Anonymous clients are rate limited per IP. Behind a load balancer,
+	// PROXY_HEADER names the header with the client IP, e.g. X-Forwarded-For,
+	// which is only read on requests from TRUSTED_PROXIES, a comma separated
+	// list of IPs and CIDR ranges.
+	v.SetDefault("PROXY_HEADER", "")
+	v.SetDefault("TRUSTED_PROXIES", "")
+	v.SetDefault("LEGACY_ROUTES_DEPRECATED_AT", "2026-10-19")
+	v.SetDefault("LEGACY_ROUTES_SUNSET", "2027-04-30")
+	v.SetDefault("GRAPHQL_MAX_DEPTH", 10)
+	v.SetDefault("GRAPHQL_MAX_COMPLEXITY", 1000)
+	v.SetDefault("RATE_LIMIT_ROUTES", "POST /login=10/1m,POST /register=5/1m,POST /renew_token=30/1m,POST /users/restore=5/1m,GET /auth/:provider/callback=20/1m")
+
+	file := filepath.Join(path, "app.env")
+	if flags != nil && flags.Changed("config") {
+		file, _ = flags.GetString("config")
+	} else if _, statErr := os.Stat(file); errors.Is(statErr, fs.ErrNotExist) {
+		file = ""
+	}
+	if file != "" {
+		v.SetConfigFile(file)
+		if err = v.ReadInConfig(); err != nil {
+			return
+		}
+	}
+
+	// AutomaticEnv alone only covers keys viper already knows about, keys
+	// without a default or a file entry have to be bound explicitly.
+	v.AutomaticEnv()
+	for _, key := range keys() {
+		if err = v.BindEnv(key); err != nil {
+			return
+		}
+		if flags != nil {
+			if err = v.BindPFlag(key, flags.Lookup(flagName(key))); err != nil {
+				return
+			}
+		}
+	}
+
+	err = v.Unmarshal(&config)
+	if err != nil {
+		return
+	}
+
+	// Replica DSNs may contain spaces, so they are separated by commas.
+	for _, url := range strings.Split(config.DbReplicaUrlList, ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			config.DbReplicaUrls = append(config.DbReplicaUrls, url)
+		}
+	}
+
+	for _, proxy := range strings.Split(config.TrustedProxyList, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			config.TrustedProxies = append(config.TrustedProxies, proxy)
+		}
+	}
 
-	viper.AutomaticEnv()
+	config.OidcProviders, err = loadOidcProviders(v, config.OidcProviderNames)
+	if err != nil {
+		return
+	}
 
-	err = viper.ReadInConfig()
+	config.RateLimitDefault, config.RateLimitRoutes, err = loadRateLimits(config.RateLimitDefaultRate, config.RateLimitRouteList)
 	if err != nil {
 		return
 	}
 
-	err = viper.Unmarshal(&config)
+	// The unprefixed routes are deprecated aliases of /v1, the dates are
+	// sent in their Deprecation and Sunset headers.
+	if config.LegacyDeprecatedAt, err = parseDate("LEGACY_ROUTES_DEPRECATED_AT", config.LegacyDeprecationDate); err != nil {
+		return
+	}
+	if config.LegacySunset, err = parseDate("LEGACY_ROUTES_SUNSET", config.LegacySunsetDate); err != nil {
+		return
+	}
+
+	err = config.Validate()
 	return
 }
+
+// keys returns the variable names of the settings in declaration order.
+func keys() []string {
+	var keys []string
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		if key := t.Field(i).Tag.Get("mapstructure"); key != "" && key != "-" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// HttpAddress is the address the server listens on.
+func (c Config) HttpAddress() string {
+	return net.JoinHostPort(c.HttpHost, c.HttpPort)
+}
+
+func loadOidcProviders(v *viper.Viper, names string) ([]OidcProvider, error) {
+	var providers []OidcProvider
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OidcProvider{
+			Name:         strings.ToLower(name),
+			Issuer:       v.GetString(prefix + "ISSUER"),
+			ClientID:     v.GetString(prefix + "CLIENT_ID"),
+			ClientSecret: v.GetString(prefix + "CLIENT_SECRET"),
+			RedirectURL:  v.GetString(prefix + "REDIRECT_URL"),
+			Scopes:       strings.Fields(v.GetString(prefix + "SCOPES")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return nil, fmt.Errorf("oidc provider %s requires %sISSUER, %sCLIENT_ID and %sREDIRECT_URL", name, prefix, prefix, prefix)
+		}
+		providers = append(providers, provider)
+	}
+	return providers, nil
+}
+
+func loadRateLimits(defaultRate, routes string) (Rate, []RouteRate, error) {
+	rate, err := ParseRate(defaultRate)
+	if err != nil {
+		return Rate{}, nil, fmt.Errorf("RATE_LIMIT_DEFAULT: %w", err)
+	}
+
+	var rates []RouteRate
+	for _, route := range strings.Split(routes, ",") {
+		if route = strings.TrimSpace(route); route == "" {
+			continue
+		}
+		target, value, _ := strings.Cut(route, "=")
+		method, path, ok := strings.Cut(strings.TrimSpace(target), " ")
+		if !ok || !strings.HasPrefix(path, "/") {
+			return Rate{}, nil, fmt.Errorf("RATE_LIMIT_ROUTES: route %q must be written as METHOD /path=limit/period", route)
+		}
+		r, err := ParseRate(value)
+		if err != nil {
+			return Rate{}, nil, fmt.Errorf("RATE_LIMIT_ROUTES: %w", err)
+		}
+		rates = append(rates, RouteRate{strings.ToUpper(method), strings.TrimSpace(path), r})
+	}
+	return rate, rates, nil
+}
+
+// parseDate reads a YYYY-MM-DD date, an empty value is the zero time.
+func parseDate(key, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	date, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a date like 2006-01-02, got %q", key, value)
+	}
+	return date, nil
+}
@@ -0,0 +1,50 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+const redacted = "<redacted>"
+
+// Dump writes the effective settings in app.env format. Values of fields
+// tagged secret, and OIDC client secrets, are redacted when set.
+func (c Config) Dump(w io.Writer) error {
+	v := reflect.ValueOf(c)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" || key == "-" {
+			continue
+		}
+
+		value := fmt.Sprint(v.Field(i).Interface())
+		if field.Tag.Get("secret") == "true" && value != "" {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", key, value); err != nil {
+			return err
+		}
+	}
+
+	for _, provider := range c.OidcProviders {
+		prefix := "OIDC_" + strings.ToUpper(provider.Name) + "_"
+		secret := provider.ClientSecret
+		if secret != "" {
+			secret = redacted
+		}
+		_, err := fmt.Fprintf(w, "%sISSUER=%s\n%sCLIENT_ID=%s\n%sCLIENT_SECRET=%s\n%sREDIRECT_URL=%s\n%sSCOPES=%s\n",
+			prefix, provider.Issuer,
+			prefix, provider.ClientID,
+			prefix, secret,
+			prefix, provider.RedirectURL,
+			prefix, strings.Join(provider.Scopes, " "))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// minTokenKeyLength matches the key size the JWT maker accepts.
+const minTokenKeyLength = 32
+
+// ValidationError lists every invalid setting, so they can all be fixed at
+// once.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  " + strings.Join(e.Problems, "\n  ")
+}
+
+// Validate checks the settings the service can't run without.
+func (c Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if port, err := strconv.Atoi(c.HttpPort); err != nil || port < 1 || port > 65535 {
+		add("HTTP_PORT must be a port number, got %q", c.HttpPort)
+	}
+	if c.UsersServiceAddress != "" {
+		host, port, err := net.SplitHostPort(c.UsersServiceAddress)
+		if err != nil || port == "" {
+			add("USERS_SERVICE_ADDRESS must be host:port or empty, got %q", c.UsersServiceAddress)
+		} else if c.UsersServiceToken == "" && !isLoopback(host) {
+			add("USERS_SERVICE_TOKEN is required when USERS_SERVICE_ADDRESS is not a loopback address, got %q", c.UsersServiceAddress)
+		}
+	}
+	if c.Environment != "dev" && c.Environment != "prod" {
+		add("ENVIRONMENT must be dev or prod, got %q", c.Environment)
+	}
+	if c.LogLevel != "" {
+		if _, err := logrus.ParseLevel(c.LogLevel); err != nil {
+			add("LOG_LEVEL must be one of panic, fatal, error, warn, info, debug or trace, got %q", c.LogLevel)
+		}
+	}
+
+	switch c.TracingExporter {
+	case "none", "otlp", "stdout", "file":
+	default:
+		add("TRACING_EXPORTER must be none, otlp, stdout or file, got %q", c.TracingExporter)
+	}
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		add("TRACING_SAMPLE_RATIO must be between 0 and 1, got %v", c.TracingSampleRatio)
+	}
+
+	switch c.DbDriver {
+	case "postgres", "mysql", "sqlite":
+	default:
+		add("DB_DRIVER must be postgres, mysql or sqlite, got %q", c.DbDriver)
+	}
+	if c.DbUrl == "" {
+		add("DB_URL is required")
+	}
+	if c.DbMaxOpenConns < 0 || c.DbMaxIdleConns < 0 {
+		add("DB_MAX_OPEN_CONNS and DB_MAX_IDLE_CONNS must not be negative")
+	}
+
+	if len(c.TokenKey) < minTokenKeyLength {
+		add("TOKEN_KEY must be at least %d characters, got %d", minTokenKeyLength, len(c.TokenKey))
+	}
+
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout},
+		{"ACCESS_TOKEN_DURATION", c.AccessTokenDuration},
+		{"REFRESH_TOKEN_DURATION", c.RefreshTokenDuration},
+		{"OIDC_STATE_DURATION", c.OidcStateDuration},
+		{"ACCOUNT_DELETION_GRACE_PERIOD", c.DeletionGracePeriod},
+		{"ACCOUNT_PURGE_INTERVAL", c.PurgeInterval},
+		{"EXPORT_TTL", c.ExportTTL},
+	} {
+		if d.value <= 0 {
+			add("%s must be a positive duration, got %s", d.key, d.value)
+		}
+	}
+	for _, d := range []struct {
+		key   string
+		value time.Duration
+	}{
+		{"DB_CONN_MAX_LIFETIME", c.DbConnMaxLifetime},
+		{"DB_CONN_MAX_IDLE_TIME", c.DbConnMaxIdleTime},
+		{"DB_STATEMENT_TIMEOUT", c.DbStatementTimeout},
+	} {
+		if d.value < 0 {
+			add("%s must not be negative, got %s", d.key, d.value)
+		}
+	}
+
+	if c.PasswordMinLength < 1 || c.PasswordMaxLength < c.PasswordMinLength {
+		add("PASSWORD_MIN_LENGTH must be positive and not above PASSWORD_MAX_LENGTH, got %d and %d", c.PasswordMinLength, c.PasswordMaxLength)
+	}
+	if c.Argon2Memory == 0 || c.Argon2Iterations == 0 || c.Argon2Parallelism == 0 {
+		add("ARGON2_MEMORY, ARGON2_ITERATIONS and ARGON2_PARALLELISM must be positive")
+	}
+
+	switch c.RateLimitStore {
+	case "memory", "database", "none":
+	default:
+		add("RATE_LIMIT_STORE must be memory, database or none, got %q", c.RateLimitStore)
+	}
+	if c.ProxyHeader != "" && len(c.TrustedProxies) == 0 {
+		add("TRUSTED_PROXIES is required with PROXY_HEADER, otherwise any client can set its IP")
+	}
+	for _, proxy := range c.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			add("TRUSTED_PROXIES must list IPs or CIDR ranges, got %q", proxy)
+		}
+	}
+
+	if c.ExportWorkers < 1 {
+		add("EXPORT_WORKERS must be positive, got %d", c.ExportWorkers)
+	}
+
+	if c.GraphQLMaxDepth < 1 || c.GraphQLMaxComplexity < 1 {
+		add("GRAPHQL_MAX_DEPTH and GRAPHQL_MAX_COMPLEXITY must be positive, got %d and %d", c.GraphQLMaxDepth, c.GraphQLMaxComplexity)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{problems}
+	}
+	return nil
+}
+
+// isLoopback reports whether a listen host only accepts local connections,
+// an empty host listens on every interface.
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+// Cluster is the primary database with its optional read replicas. Writes
+// and reads that must see them always go to the primary.
+type Cluster struct {
+	Primary  *gorm.DB
+	Replicas []*gorm.DB
+	next     uint32
+}
+
+// Reader returns the database for reads that tolerate replication lag. It
+// rotates through the replicas and falls back to the primary without any.
+func (c *Cluster) Reader() *gorm.DB {
+	if len(c.Replicas) == 0 {
+		return c.Primary
+	}
+	i := atomic.AddUint32(&c.next, 1)
+	return c.Replicas[int(i)%len(c.Replicas)]
+}
+
+func (c *Cluster) Close() error {
+	var firstErr error
+	for _, db := range append([]*gorm.DB{c.Primary}, c.Replicas...) {
+		sqlDB, err := db.DB()
+		if err == nil {
+			err = sqlDB.Close()
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"gorm.io/gorm"
+)
+
+func memoryUrl() string {
+	return fmt.Sprintf("file:%s?mode=memory&cache=shared", pkg.RandomString(16))
+}
+
+func TestCluster(t *testing.T) {
+	cluster, err := Connect(config.Config{
+		DbDriver:           DriverSqlite,
+		DbUrl:              memoryUrl(),
+		DbReplicaUrls:      []string{memoryUrl(), memoryUrl()},
+		DbMaxOpenConns:     3,
+		DbMaxIdleConns:     1,
+		DbStatementTimeout: time.Second,
+	})
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	first, second := cluster.Reader(), cluster.Reader()
+	require.NotSame(t, first, second)
+	require.NotSame(t, cluster.Primary, first)
+	require.Same(t, first, cluster.Reader())
+
+	var one int
+	require.NoError(t, first.Raw("SELECT 1").Scan(&one).Error)
+	require.Equal(t, 1, one)
+
+	// Raw().Scan runs through the row callbacks, with the statement timeout.
+	var deadline bool
+	require.NoError(t, first.Callback().Row().After("timeout:before_row").Register("test:deadline", func(tx *gorm.DB) {
+		_, deadline = tx.Statement.Context.Deadline()
+	}))
+	require.NoError(t, first.Raw("SELECT 1").Scan(&one).Error)
+	require.True(t, deadline)
+
+	sqlDB, err := cluster.Primary.DB()
+	require.NoError(t, err)
+	require.Equal(t, 3, sqlDB.Stats().MaxOpenConnections)
+}
+
+func TestReaderWithoutReplicas(t *testing.T) {
+	cluster, err := Connect(config.Config{DbDriver: DriverSqlite, DbUrl: memoryUrl()})
+	require.NoError(t, err)
+	defer cluster.Close()
+
+	require.Same(t, cluster.Primary, cluster.Reader())
+}
@@ -1,21 +1,106 @@
 package database
 
 import (
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	mysqldriver "github.com/go-sql-driver/mysql"
 	"github.com/zura-t/bookstore_fiber/config"
+	"gorm.io/driver/mysql"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverMysql    = "mysql"
+	DriverSqlite   = "sqlite"
+)
+
 var (
 	DbConn *gorm.DB
 )
 
-func Connect(config config.Config) (*gorm.DB, error) {
+// Connect opens the primary database and every read replica listed in
+// DB_REPLICA_URLS, with the same pool settings for all of them.
+func Connect(config config.Config) (*Cluster, error) {
 	// dsn := "host=localhost user=postgres password=root dbname=book_store port=5432 sslmode=disable"
-	var err error
-	DbConn, err = gorm.Open(postgres.Open(config.DbUrl), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Silent),
+	primary, err := open(config, config.DbUrl)
+	if err != nil {
+		return nil, err
+	}
+	DbConn = primary
+
+	cluster := &Cluster{Primary: primary}
+	for i, url := range config.DbReplicaUrls {
+		replica, err := open(config, url)
+		if err != nil {
+			cluster.Close()
+			return nil, fmt.Errorf("replica %d: %w", i+1, err)
+		}
+		cluster.Replicas = append(cluster.Replicas, replica)
+	}
+	return cluster, nil
+}
+
+func open(config config.Config, dsn string) (*gorm.DB, error) {
+	dialector, err := Dialector(config.DbDriver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger:         logger.Default.LogMode(logger.Silent),
+		TranslateError: true,
 	})
-	return DbConn, err
+	if err != nil {
+		return nil, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// Zero keeps the database/sql default, which for idle connections is not
+	// the same as SetMaxIdleConns(0) and matters for in-memory SQLite.
+	if config.DbMaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(config.DbMaxOpenConns)
+	}
+	if config.DbMaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(config.DbMaxIdleConns)
+	}
+	sqlDB.SetConnMaxLifetime(config.DbConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.DbConnMaxIdleTime)
+
+	if config.DbStatementTimeout > 0 {
+		if err := registerStatementTimeout(db, config.DbStatementTimeout); err != nil {
+			return nil, err
+		}
+	}
+	return db, nil
+}
+
+// Dialector returns the GORM dialector for a DB_DRIVER value. The DSN format
+// is the one of the underlying driver, e.g. "file:bookstore.db" for SQLite.
+func Dialector(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "", DriverPostgres:
+		return postgres.Open(dsn), nil
+	case DriverMysql:
+		cfg, err := mysqldriver.ParseDSN(dsn)
+		if err != nil {
+			return nil, err
+		}
+		// Migrations run several statements at once, the models need DATETIME
+		// columns scanned into time.Time, and updates that change nothing
+		// must still count the matched rows.
+		cfg.MultiStatements = true
+		cfg.ParseTime = true
+		cfg.ClientFoundRows = true
+		return mysql.Open(cfg.FormatDSN()), nil
+	case DriverSqlite:
+		return sqlite.Open(dsn), nil
+	}
+	return nil, fmt.Errorf("unsupported DB_DRIVER %q, expected %s, %s or %s", driver, DriverPostgres, DriverMysql, DriverSqlite)
 }
@@ -0,0 +1,47 @@
+package database
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const cancelKey = "database:cancel_statement"
+
+// registerStatementTimeout bounds every statement run through GORM with a
+// context deadline, so the driver cancels queries that run for too long.
+// Row and Rows, which Raw().Scan uses, return before their rows are read, so
+// their context is released by its deadline instead of after the callback.
+func registerStatementTimeout(db *gorm.DB, timeout time.Duration) error {
+	before := func(tx *gorm.DB) {
+		ctx, cancel := context.WithTimeout(tx.Statement.Context, timeout)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(cancelKey, cancel)
+	}
+	after := func(tx *gorm.DB) {
+		if cancel, ok := tx.InstanceGet(cancelKey); ok {
+			cancel.(context.CancelFunc)()
+		}
+	}
+
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("timeout:before_create", before),
+		callbacks.Create().After("gorm:after_create").Register("timeout:after_create", after),
+		callbacks.Query().Before("gorm:query").Register("timeout:before_query", before),
+		callbacks.Query().After("gorm:after_query").Register("timeout:after_query", after),
+		callbacks.Update().Before("gorm:update").Register("timeout:before_update", before),
+		callbacks.Update().After("gorm:after_update").Register("timeout:after_update", after),
+		callbacks.Delete().Before("gorm:delete").Register("timeout:before_delete", before),
+		callbacks.Delete().After("gorm:after_delete").Register("timeout:after_delete", after),
+		callbacks.Row().Before("gorm:row").Register("timeout:before_row", before),
+		callbacks.Raw().Before("gorm:raw").Register("timeout:before_raw", before),
+		callbacks.Raw().After("gorm:raw").Register("timeout:after_raw", after),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
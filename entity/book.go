@@ -6,8 +6,23 @@ type Book struct {
 	Id          uint      `json:"id"`
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
+	Price       uint      `json:"price"`
+	File        string    `json:"file"`
 	Author      User      `json:"author"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 	DeletedAt   time.Time `json:"deleted_at"`
 }
+
+type Author struct {
+	User
+	BooksCount int `json:"books_count"`
+}
+
+// BookSales is what an author sees about the demand for one of their books.
+// Books can't be bought yet, so it counts the carts and read lists holding it.
+type BookSales struct {
+	Book
+	InCarts     int `json:"in_carts"`
+	InReadLists int `json:"in_read_lists"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+type CartItem struct {
+	Id        uint      `json:"id"`
+	UserId    uint      `json:"user_id"`
+	Book      Book      `json:"book"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ReadListEntry struct {
+	Book    Book      `json:"book"`
+	AddedAt time.Time `json:"added_at"`
+}
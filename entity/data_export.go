@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+const (
+	ExportPending = "pending"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+	ExportExpired = "expired"
+)
+
+type DataExport struct {
+	Id        string     `json:"id"`
+	UserId    uint       `json:"user_id"`
+	Status    string     `json:"status"`
+	Error     string     `json:"error"`
+	File      string     `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
@@ -7,6 +7,7 @@ type User struct {
 	Email     string    `json:"email"`
 	Name      string    `json:"name"`
 	IsAuthor  bool      `json:"is_author"`
+	Locale    string    `json:"locale"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at"`
@@ -0,0 +1,143 @@
+// Package i18n translates the messages sent to clients. The catalogs in
+// locales/ map message keys to texts with {0}, {1}... placeholders, one file
+// per language, and are loaded into universal-translator at startup.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/ru"
+	ut "github.com/go-playground/universal-translator"
+)
+
+// DefaultLocale is used when the client accepts none of the catalogs.
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var catalogs embed.FS
+
+// rules are the plural and number rules of every language with a catalog.
+var rules = map[string]locales.Translator{
+	"en": en.New(),
+	"ru": ru.New(),
+}
+
+var placeholder = regexp.MustCompile(`\{[0-9]+\}`)
+
+var (
+	universal *ut.UniversalTranslator
+	supported []string
+	// arity is the number of placeholders of every key, universal-translator
+	// panics when T gets fewer params.
+	arity = make(map[string]int)
+)
+
+func init() {
+	var err error
+	universal, supported, err = load()
+	if err != nil {
+		panic(err)
+	}
+}
+
+func load() (*ut.UniversalTranslator, []string, error) {
+	files, err := catalogs.ReadDir("locales")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	universal := ut.New(rules[DefaultLocale])
+	var names []string
+	for _, file := range files {
+		locale := strings.TrimSuffix(file.Name(), path.Ext(file.Name()))
+		r, ok := rules[locale]
+		if !ok {
+			return nil, nil, fmt.Errorf("i18n: no language rules for catalog %s", file.Name())
+		}
+		if err := universal.AddTranslator(r, true); err != nil {
+			return nil, nil, err
+		}
+
+		messages, err := readCatalog(file.Name())
+		if err != nil {
+			return nil, nil, err
+		}
+		trans, _ := universal.GetTranslator(locale)
+		for key, text := range messages {
+			if err := trans.Add(key, text, false); err != nil {
+				return nil, nil, fmt.Errorf("i18n: %s: %s: %w", file.Name(), key, err)
+			}
+			arity[locale+":"+key] = len(placeholder.FindAllString(text, -1))
+		}
+		names = append(names, locale)
+	}
+
+	// The default goes first, it is what clients accepting anything get.
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] == DefaultLocale || (names[j] != DefaultLocale && names[i] < names[j])
+	})
+	return universal, names, nil
+}
+
+// readCatalog flattens the sections of a catalog into "section.key" keys.
+func readCatalog(name string) (map[string]string, error) {
+	data, err := catalogs.ReadFile("locales/" + name)
+	if err != nil {
+		return nil, err
+	}
+
+	var sections map[string]map[string]string
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("i18n: %s: %w", name, err)
+	}
+
+	messages := make(map[string]string)
+	for section, texts := range sections {
+		for key, text := range texts {
+			messages[section+"."+key] = text
+		}
+	}
+	return messages, nil
+}
+
+// Supported returns the locales with a catalog, the default first.
+func Supported() []string {
+	return append([]string(nil), supported...)
+}
+
+func IsSupported(locale string) bool {
+	for _, l := range supported {
+		if l == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// T returns the text of key in locale, falling back to the default locale.
+// It reports false when neither catalog has the key or params are missing.
+// Placeholders are filled in the order they appear in the text.
+func T(locale, key string, params ...string) (string, bool) {
+	for _, l := range []string{locale, DefaultLocale} {
+		trans, found := universal.GetTranslator(l)
+		if !found {
+			continue
+		}
+		n, ok := arity[l+":"+key]
+		if !ok || len(params) < n {
+			continue
+		}
+		if text, err := trans.T(key, params...); err == nil {
+			return text, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,77 @@
+package i18n
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalogsMatch(t *testing.T) {
+	base, err := readCatalog(DefaultLocale + ".json")
+	require.NoError(t, err)
+
+	for _, locale := range Supported()[1:] {
+		messages, err := readCatalog(locale + ".json")
+		require.NoError(t, err)
+		require.Len(t, messages, len(base), locale)
+		for key := range base {
+			_, ok := messages[key]
+			require.True(t, ok, "%s has no %s", locale, key)
+			require.Equal(t, arity[DefaultLocale+":"+key], arity[locale+":"+key], "%s: %s", locale, key)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	text, ok := T("ru", "validation.required", "email", "")
+	require.True(t, ok)
+	require.Contains(t, text, "email")
+
+	english, ok := T("de", "validation.required", "email", "")
+	require.True(t, ok)
+	require.Equal(t, "email is required", english)
+
+	_, ok = T("en", "validation.required")
+	require.False(t, ok)
+	_, ok = T("en", "unknown.key")
+	require.False(t, ok)
+}
+
+func TestLocale(t *testing.T) {
+	app := fiber.New()
+	app.Use(Middleware(func(c *fiber.Ctx) string { return c.Query("preference") }))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendString(Locale(c))
+	})
+
+	tests := []struct {
+		name       string
+		preference string
+		accept     string
+		locale     string
+	}{
+		{"default", "", "", "en"},
+		{"accept language", "", "ru-RU, en;q=0.5", "ru"},
+		{"unsupported", "", "de", "en"},
+		{"preference", "ru", "en", "ru"},
+		{"unsupported preference", "de", "ru", "ru"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/?preference="+tc.preference, nil)
+			if tc.accept != "" {
+				req.Header.Set(fiber.HeaderAcceptLanguage, tc.accept)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body := make([]byte, 8)
+			n, _ := resp.Body.Read(body)
+			require.Equal(t, tc.locale, string(body[:n]))
+			require.Equal(t, fiber.HeaderAcceptLanguage, resp.Header.Get(fiber.HeaderVary))
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package i18n
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	localeKey     = "locale"
+	preferenceKey = "locale_preference"
+)
+
+// Middleware lets Locale consult preference, which returns the language the
+// user chose in their profile or "" if they didn't. It is only called when a
+// response is translated, so usually after authentication.
+func Middleware(preference func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(preferenceKey, preference)
+		c.Vary(fiber.HeaderAcceptLanguage)
+		return c.Next()
+	}
+}
+
+// Locale negotiates the language of the response: the user's preference,
+// then the Accept-Language header, then DefaultLocale.
+func Locale(c *fiber.Ctx) string {
+	if locale, ok := c.Locals(localeKey).(string); ok {
+		return locale
+	}
+
+	locale := ""
+	if preference, ok := c.Locals(preferenceKey).(func(c *fiber.Ctx) string); ok {
+		if preferred := preference(c); IsSupported(preferred) {
+			locale = preferred
+		}
+	}
+	if locale == "" {
+		locale = c.AcceptsLanguages(supported...)
+	}
+	if locale == "" {
+		locale = DefaultLocale
+	}
+
+	c.Locals(localeKey, locale)
+	return locale
+}
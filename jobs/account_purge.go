@@ -0,0 +1,163 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+const (
+	BooksPolicyUnpublish = "unpublish"
+	BooksPolicyReassign  = "reassign"
+)
+
+// AccountPurger anonymizes accounts whose deletion grace period has ended.
+// The user row is kept so that records referencing it stay valid, while
+// personal data, sessions, read list, cart and data exports are removed and
+// authored books are unpublished or reassigned according to the configured
+// policy.
+type AccountPurger struct {
+	log    *logrus.Logger
+	db     *gorm.DB
+	config config.Config
+}
+
+func NewAccountPurger(log *logrus.Logger, db *gorm.DB, config config.Config) (*AccountPurger, error) {
+	switch config.DeletedAuthorBooks {
+	case BooksPolicyUnpublish:
+	case BooksPolicyReassign:
+		if config.DeletedAuthorReassign == 0 {
+			return nil, fmt.Errorf("DELETED_AUTHOR_BOOKS_REASSIGN_TO is required for the %q policy", BooksPolicyReassign)
+		}
+		if err := checkReassignTarget(db.WithContext(context.Background()), config.DeletedAuthorReassign); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown deleted author books policy %q", config.DeletedAuthorBooks)
+	}
+	return &AccountPurger{log, db, config}, nil
+}
+
+// checkReassignTarget makes sure books are reassigned to an author that can
+// still manage them.
+func checkReassignTarget(db *gorm.DB, id uint) error {
+	var author models.User
+	err := db.Where("is_author = ?", true).First(&author, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("DELETED_AUTHOR_BOOKS_REASSIGN_TO must be an existing author, user %d is not", id)
+	}
+	return err
+}
+
+// Run purges expired accounts and OIDC login states every PurgeInterval
+// until ctx is cancelled.
+func (p *AccountPurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := p.PurgeExpired(ctx)
+		if err != nil {
+			p.log.Error(err)
+		} else if purged > 0 {
+			p.log.WithFields(logrus.Fields{
+				"purged": purged,
+			}).Info("Purged deleted accounts")
+		}
+
+		if err := p.PurgeOidcStates(ctx); err != nil {
+			p.log.Error(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeOidcStates deletes the login states of OIDC flows that were never
+// completed.
+func (p *AccountPurger) PurgeOidcStates(ctx context.Context) error {
+	return p.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&models.OidcState{}).Error
+}
+
+func (p *AccountPurger) PurgeExpired(ctx context.Context) (int, error) {
+	var users []models.User
+	deadline := time.Now().Add(-p.config.DeletionGracePeriod)
+	err := p.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND anonymized_at IS NULL", deadline).
+		Find(&users).Error
+	if err != nil {
+		return 0, err
+	}
+
+	for i, user := range users {
+		if err := p.purge(ctx, user); err != nil {
+			return i, fmt.Errorf("failed to purge user %d: %w", user.ID, err)
+		}
+	}
+	return len(users), nil
+}
+
+func (p *AccountPurger) purge(ctx context.Context, user models.User) error {
+	return p.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var err error
+		switch p.config.DeletedAuthorBooks {
+		case BooksPolicyReassign:
+			// The author may have been deleted since startup.
+			if err := checkReassignTarget(tx, p.config.DeletedAuthorReassign); err != nil {
+				return err
+			}
+			err = tx.Model(&models.Book{}).Where("author_id = ?", user.ID).
+				Update("author_id", p.config.DeletedAuthorReassign).Error
+		default:
+			err = tx.Where("author_id = ?", user.ID).Delete(&models.Book{}).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		var exports []models.DataExport
+		if err := tx.Where("user_id = ?", user.ID).Find(&exports).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&models.UserBook{}, &models.CartItem{}, &models.UserIdentity{}, &models.ApiKey{}, &models.DataExport{}} {
+			if err := tx.Where("user_id = ?", user.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		err = tx.Unscoped().Model(&user).Updates(map[string]interface{}{
+			"name":          "Deleted user",
+			"email":         fmt.Sprintf("deleted-%d@deleted.invalid", user.ID),
+			"password":      "",
+			"is_author":     false,
+			"anonymized_at": time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		// The archives go last, a failure rolls the purge back and it is
+		// retried with the rows still pointing at the remaining files.
+		for _, export := range exports {
+			if export.File == "" {
+				continue
+			}
+			if err := os.Remove(export.File); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,441 @@
+package jobs
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"gorm.io/gorm"
+)
+
+// staleExportAge marks pending exports abandoned, e.g. by a restart.
+const staleExportAge = time.Hour
+
+// exportQueueSize bounds the exports waiting for a worker.
+const exportQueueSize = 100
+
+var (
+	// ErrExportQueueFull is returned by Request when too many exports wait
+	// for a worker.
+	ErrExportQueueFull = errors.New("export queue is full")
+	// ErrExporterClosed is returned by Request after Shutdown.
+	ErrExporterClosed = errors.New("data exporter is shut down")
+)
+
+// DataExporter builds personal data exports on ExportWorkers background
+// workers and removes them once they expire.
+type DataExporter struct {
+	log    *logrus.Logger
+	db     *gorm.DB
+	config config.Config
+
+	// mu serializes Request, so that a user gets one pending export and
+	// sends to queue never block.
+	mu      sync.Mutex
+	closed  bool
+	queue   chan models.DataExport
+	workers sync.WaitGroup
+	// stop cancels the builds still running when Shutdown gives up.
+	ctx  context.Context
+	stop context.CancelFunc
+}
+
+func NewDataExporter(log *logrus.Logger, db *gorm.DB, config config.Config) *DataExporter {
+	ctx, stop := context.WithCancel(context.Background())
+	e := &DataExporter{
+		log:    log,
+		db:     db,
+		config: config,
+		queue:  make(chan models.DataExport, exportQueueSize),
+		ctx:    ctx,
+		stop:   stop,
+	}
+	for i := 0; i < config.ExportWorkers; i++ {
+		e.workers.Add(1)
+		go e.work()
+	}
+	return e
+}
+
+// Request queues an export of the user's data. A pending export of the user
+// is returned instead of starting another one.
+func (e *DataExporter) Request(ctx context.Context, userId uint) (*models.DataExport, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return nil, ErrExporterClosed
+	}
+
+	db := e.db.WithContext(ctx)
+	var pending models.DataExport
+	err := db.Where("user_id = ? AND status = ? AND created_at >= ?", userId, models.ExportPending, time.Now().Add(-staleExportAge)).
+		Order("created_at DESC").First(&pending).Error
+	if err == nil {
+		return &pending, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if len(e.queue) == cap(e.queue) {
+		return nil, ErrExportQueueFull
+	}
+
+	export := models.DataExport{
+		ID:     uuid.NewString(),
+		UserID: userId,
+		Status: models.ExportPending,
+	}
+	if err := db.Create(&export).Error; err != nil {
+		return nil, err
+	}
+
+	e.queue <- export
+
+	return &export, nil
+}
+
+// Shutdown stops accepting exports and waits for the queued ones to be
+// built. Builds still running when ctx is done are cancelled, Cleanup fails
+// them later.
+func (e *DataExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.queue)
+	}
+	e.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		e.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		e.stop()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (e *DataExporter) work() {
+	defer e.workers.Done()
+
+	for export := range e.queue {
+		if e.ctx.Err() != nil {
+			continue
+		}
+		e.build(export)
+	}
+}
+
+func (e *DataExporter) build(export models.DataExport) {
+	path := filepath.Join(e.config.ExportDir, export.ID+".zip")
+	updates := map[string]interface{}{}
+
+	// Exports are built after the request that asked for them has returned,
+	// so they are traced on their own.
+	ctx, span := tracing.Start(e.ctx, "export.build", attribute.String("export.id", export.ID))
+	err := e.writeArchive(ctx, export.UserID, path)
+	tracing.End(span, err)
+	if err != nil {
+		e.log.WithFields(logrus.Fields{
+			"export_id": export.ID,
+		}).Error(err)
+		os.Remove(path)
+		updates["status"] = models.ExportFailed
+		updates["error"] = "export failed"
+	} else {
+		updates["status"] = models.ExportReady
+		updates["file"] = path
+		updates["expires_at"] = time.Now().Add(e.config.ExportTTL)
+	}
+
+	res := e.db.Model(&export).Updates(updates)
+	if res.Error != nil {
+		e.log.WithFields(logrus.Fields{
+			"export_id": export.ID,
+		}).Error(res.Error)
+	} else if res.RowsAffected == 0 {
+		// The account was purged while the export was being built.
+		os.Remove(path)
+	}
+}
+
+type exportedUser struct {
+	Id        uint      `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	IsAuthor  bool      `json:"is_author"`
+	Locale    string    `json:"locale"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type exportedBook struct {
+	Id          uint      `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	Price       uint      `json:"price"`
+	AuthorID    uint      `json:"author_id"`
+	File        string    `json:"file,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type exportedListItem struct {
+	Book    exportedBook `json:"book"`
+	AddedAt time.Time    `json:"added_at"`
+}
+
+type exportedApiKey struct {
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+}
+
+type exportedIdentity struct {
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type exportedSessions struct {
+	ApiKeys    []exportedApiKey   `json:"api_keys"`
+	Identities []exportedIdentity `json:"linked_identities"`
+}
+
+func (e *DataExporter) writeArchive(ctx context.Context, userId uint, path string) error {
+	db := e.db.WithContext(ctx)
+
+	var user models.User
+	if err := db.First(&user, userId).Error; err != nil {
+		return err
+	}
+
+	var readList []models.UserBook
+	if err := db.Preload("Book", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Where("user_id = ?", userId).Order("created_at").Find(&readList).Error; err != nil {
+		return err
+	}
+
+	var cart []models.CartItem
+	if err := db.Preload("Book", func(tx *gorm.DB) *gorm.DB { return tx.Unscoped() }).
+		Where("user_id = ?", userId).Order("created_at").Find(&cart).Error; err != nil {
+		return err
+	}
+
+	var books []models.Book
+	if err := db.Where("author_id = ?", userId).Order("created_at").Find(&books).Error; err != nil {
+		return err
+	}
+
+	var apiKeys []models.ApiKey
+	if err := db.Where("user_id = ?", userId).Order("created_at").Find(&apiKeys).Error; err != nil {
+		return err
+	}
+
+	var identities []models.UserIdentity
+	if err := db.Where("user_id = ?", userId).Order("created_at").Find(&identities).Error; err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+
+	profile := exportedUser{
+		Id:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		IsAuthor:  user.IsAuthor,
+		Locale:    user.Locale,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+	if err := writeJSON(archive, "profile.json", profile); err != nil {
+		return err
+	}
+
+	sessions := exportedSessions{ApiKeys: []exportedApiKey{}, Identities: []exportedIdentity{}}
+	for _, k := range apiKeys {
+		sessions.ApiKeys = append(sessions.ApiKeys, exportedApiKey{
+			Name:       k.Name,
+			Prefix:     k.Prefix,
+			Scopes:     k.ScopeList(),
+			CreatedAt:  k.CreatedAt,
+			ExpiresAt:  k.ExpiresAt,
+			LastUsedAt: k.LastUsedAt,
+			RevokedAt:  k.RevokedAt,
+		})
+	}
+	for _, i := range identities {
+		sessions.Identities = append(sessions.Identities, exportedIdentity{
+			Provider:  i.Provider,
+			Subject:   i.Subject,
+			Email:     i.Email,
+			CreatedAt: i.CreatedAt,
+		})
+	}
+	if err := writeJSON(archive, "sessions.json", sessions); err != nil {
+		return err
+	}
+
+	items := []exportedListItem{}
+	for _, v := range readList {
+		items = append(items, exportedListItem{Book: convertExportedBook(v.Book, false), AddedAt: v.CreatedAt})
+	}
+	if err := writeJSON(archive, "read_list.json", items); err != nil {
+		return err
+	}
+
+	items = []exportedListItem{}
+	for _, v := range cart {
+		items = append(items, exportedListItem{Book: convertExportedBook(v.Book, false), AddedAt: v.CreatedAt})
+	}
+	if err := writeJSON(archive, "cart.json", items); err != nil {
+		return err
+	}
+
+	uploaded := []exportedBook{}
+	for _, b := range books {
+		uploaded = append(uploaded, convertExportedBook(b, true))
+	}
+	if err := writeJSON(archive, "books.json", uploaded); err != nil {
+		return err
+	}
+
+	for _, b := range books {
+		if err := copyFile(archive, b); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func convertExportedBook(book models.Book, withFile bool) exportedBook {
+	res := exportedBook{
+		Id:          book.ID,
+		Title:       book.Title,
+		Description: book.Description,
+		Price:       book.Price,
+		AuthorID:    book.AuthorID,
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+	}
+	if withFile && book.File != "" {
+		res.File = exportedFileName(book)
+	}
+	return res
+}
+
+func exportedFileName(book models.Book) string {
+	return fmt.Sprintf("files/%d_%s", book.ID, filepath.Base(book.File))
+}
+
+func writeJSON(archive *zip.Writer, name string, v interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// copyFile adds an uploaded book file to the archive. Files missing on disk
+// are skipped, the metadata still references them.
+func copyFile(archive *zip.Writer, book models.Book) error {
+	if book.File == "" {
+		return nil
+	}
+	src, err := os.Open(book.File)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer src.Close()
+
+	w, err := archive.Create(exportedFileName(book))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, src)
+	return err
+}
+
+// Run removes expired export archives and fails abandoned pending exports
+// every PurgeInterval until ctx is cancelled.
+func (e *DataExporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(e.config.PurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.Cleanup(ctx); err != nil {
+			e.log.Error(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *DataExporter) Cleanup(ctx context.Context) error {
+	db := e.db.WithContext(ctx)
+
+	var expired []models.DataExport
+	err := db.Where("status = ? AND expires_at < ?", models.ExportReady, time.Now()).Find(&expired).Error
+	if err != nil {
+		return err
+	}
+
+	for _, export := range expired {
+		if err := os.Remove(export.File); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		err = db.Model(&export).Updates(map[string]interface{}{"status": models.ExportExpired, "file": ""}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return db.Model(&models.DataExport{}).
+		Where("status = ? AND created_at < ?", models.ExportPending, time.Now().Add(-staleExportAge)).
+		Updates(map[string]interface{}{"status": models.ExportFailed, "error": "export was interrupted"}).Error
+}
@@ -1,32 +1,47 @@
 package logger
 
 import (
+	"context"
 	"os"
 
 	"github.com/sirupsen/logrus"
 )
 
-const (
-	envDev  = "dev"
-	envProd = "prod"
-)
+const envProd = "prod"
 
-func SetupLogger(env string) *logrus.Logger {
-	var log *logrus.Logger
-
-	switch env {
-	case envDev:
-		log = &logrus.Logger{
-			Out:       os.Stdout,
-			Formatter: &logrus.TextFormatter{FullTimestamp: true},
-			Level:     logrus.DebugLevel,
-		}
-	case envProd:
-		log = &logrus.Logger{
-			Out:       os.Stdout,
-			Formatter: &logrus.JSONFormatter{},
-			Level:     logrus.InfoLevel,
-		}
+// SetupLogger logs JSON in prod and text otherwise. An empty level means
+// debug in dev and info in prod.
+func SetupLogger(env, level string) *logrus.Logger {
+	log := &logrus.Logger{
+		Out:       os.Stdout,
+		Formatter: &logrus.TextFormatter{FullTimestamp: true},
+		Hooks:     logrus.LevelHooks{},
+		Level:     logrus.DebugLevel,
+	}
+	if env == envProd {
+		log.Formatter = &logrus.JSONFormatter{}
+		log.Level = logrus.InfoLevel
+	}
+
+	if parsed, err := logrus.ParseLevel(level); err == nil {
+		log.Level = parsed
 	}
 	return log
 }
+
+type entryKey struct{}
+
+// NewContext returns ctx carrying entry, so code called with ctx logs with
+// the fields of the request it handles.
+func NewContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryKey{}, entry)
+}
+
+// FromContext returns the entry stored by NewContext, or an entry of log
+// without fields when there is none.
+func FromContext(ctx context.Context, log *logrus.Logger) *logrus.Entry {
+	if entry, ok := ctx.Value(entryKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+	return logrus.NewEntry(log)
+}
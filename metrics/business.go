@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+)
+
+const businessTimeout = 2 * time.Second
+
+// businessCollector counts rows on every scrape instead of keeping gauges in
+// sync with every write, which would miss changes made by other instances.
+type businessCollector struct {
+	db     *gorm.DB
+	gauges []businessGauge
+}
+
+type businessGauge struct {
+	desc  *prometheus.Desc
+	query string
+}
+
+// RegisterBusinessGauges adds gauges computed from db, which should be the
+// primary so that they don't lag.
+func (m *Metrics) RegisterBusinessGauges(db *gorm.DB) error {
+	gauge := func(name, help, query string) businessGauge {
+		return businessGauge{prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, nil, nil), query}
+	}
+	return m.registry.Register(&businessCollector{db, []businessGauge{
+		gauge("books_published", "Books that are published and not deleted.",
+			"SELECT COUNT(*) FROM books WHERE deleted_at IS NULL"),
+		gauge("carts_with_items", "Users with at least one book in their cart.",
+			"SELECT COUNT(DISTINCT user_id) FROM cart_items"),
+		gauge("users_active", "Users that are not deleted.",
+			"SELECT COUNT(*) FROM users WHERE deleted_at IS NULL"),
+	}})
+}
+
+func (c *businessCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, g := range c.gauges {
+		ch <- g.desc
+	}
+}
+
+func (c *businessCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), businessTimeout)
+	defer cancel()
+
+	for _, g := range c.gauges {
+		var count int64
+		if err := c.db.WithContext(ctx).Raw(g.query).Scan(&count).Error; err != nil {
+			ch <- prometheus.NewInvalidMetric(g.desc, err)
+			continue
+		}
+		ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, float64(count))
+	}
+}
@@ -0,0 +1,31 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Middleware records every request under its route template, e.g.
+// "/books/:id", so that ids don't create new series. Errors returned by
+// handlers are counted with the status the error handler answers with.
+func (m *Metrics) Middleware() func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		err := c.Next()
+		status := c.Response().StatusCode()
+		if err != nil {
+			status = fiber.StatusInternalServerError
+			if e, ok := err.(*fiber.Error); ok {
+				status = e.Code
+			}
+		}
+
+		route := c.Route().Path
+		m.requests.WithLabelValues(c.Method(), route, strconv.Itoa(status)).Inc()
+		m.duration.WithLabelValues(c.Method(), route).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,112 @@
+// Package metrics collects the Prometheus metrics of the bookstore and serves
+// them in the text exposition format.
+package metrics
+
+import (
+	"crypto/subtle"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"gorm.io/gorm"
+)
+
+const namespace = "bookstore"
+
+// Metrics owns a registry, so that every router built in tests gets its own.
+type Metrics struct {
+	registry      *prometheus.Registry
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	queries       *prometheus.HistogramVec
+	transferBytes *prometheus.CounterVec
+	logins        *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP requests by method, route template and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by method and route template.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		queries: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Database statement duration by operation and table.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"operation", "table"}),
+		transferBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "transfer_bytes_total",
+			Help:      "Bytes of uploaded and downloaded files by direction and kind.",
+		}, []string{"direction", "kind"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "Login attempts by method and result.",
+		}, []string{"method", "result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests, m.duration, m.queries, m.transferBytes, m.logins,
+	)
+	return m
+}
+
+// RegisterPool adds the connection pool statistics of db under name, e.g.
+// "primary".
+func (m *Metrics) RegisterPool(name string, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return m.registry.Register(collectors.NewDBStatsCollector(sqlDB, name))
+}
+
+// Upload counts the bytes of an uploaded file of kind, e.g. "book".
+func (m *Metrics) Upload(kind string, bytes int64) {
+	m.transferBytes.WithLabelValues("upload", kind).Add(float64(bytes))
+}
+
+// Download counts the bytes of a downloaded file of kind, e.g. "export".
+func (m *Metrics) Download(kind string, bytes int) {
+	if bytes > 0 {
+		m.transferBytes.WithLabelValues("download", kind).Add(float64(bytes))
+	}
+}
+
+// Login counts a login attempt with method, e.g. "password" or an OIDC
+// provider name.
+func (m *Metrics) Login(method string, ok bool) {
+	result := "success"
+	if !ok {
+		result = "failure"
+	}
+	m.logins.WithLabelValues(method, result).Inc()
+}
+
+// Handler serves the metrics. A non empty token has to be sent as a Bearer
+// token, so the endpoint can be exposed without publishing the numbers.
+func (m *Metrics) Handler(token string) func(*fiber.Ctx) error {
+	serve := adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+	expected := []byte("Bearer " + token)
+	return func(c *fiber.Ctx) error {
+		if token != "" && subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), expected) != 1 {
+			return apperror.ErrUnauthenticated
+		}
+		return serve(c)
+	}
+}
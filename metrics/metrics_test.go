@@ -0,0 +1,44 @@
+package metrics_test
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/apitest"
+	"github.com/zura-t/bookstore_fiber/config"
+)
+
+func TestMetrics(t *testing.T) {
+	h := apitest.New(t, func(c *config.Config) {
+		c.MetricsToken = "metrics-token"
+	})
+	author := h.CreateAuthor()
+	book := h.CreateBook(author)
+
+	apitest.Decode(t, h.Request(http.MethodGet, fmt.Sprintf("/books/%d", book.ID), nil, ""), http.StatusOK, nil)
+	apitest.Decode(t, h.Request(http.MethodGet, "/books/9999", nil, ""), http.StatusNotFound, nil)
+	login := map[string]string{"email": author.Email, "password": "wrong password"}
+	apitest.Decode(t, h.Request(http.MethodPost, "/login", login, ""), http.StatusUnauthorized, nil)
+
+	apitest.Decode(t, h.Request(http.MethodGet, "/metrics", nil, ""), http.StatusUnauthorized, nil)
+	apitest.Decode(t, h.Request(http.MethodGet, "/metrics", nil, "wrong-token"), http.StatusUnauthorized, nil)
+
+	resp := h.Request(http.MethodGet, "/metrics", nil, "metrics-token")
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	out := string(body)
+
+	require.Contains(t, out, `bookstore_http_requests_total{method="GET",route="/books/:id",status="200"} 1`)
+	require.Contains(t, out, `bookstore_http_requests_total{method="GET",route="/books/:id",status="404"} 1`)
+	require.Contains(t, out, `bookstore_http_request_duration_seconds_count{method="GET",route="/books/:id"} 2`)
+	require.Contains(t, out, `bookstore_logins_total{method="password",result="success"} 1`)
+	require.Contains(t, out, `bookstore_logins_total{method="password",result="failure"} 1`)
+	require.Contains(t, out, `bookstore_db_query_duration_seconds_count{operation="query",table="books"}`)
+	require.Contains(t, out, "bookstore_books_published 1\n")
+	require.Contains(t, out, "bookstore_carts_with_items 0\n")
+	require.Contains(t, out, `go_sql_open_connections{db_name="primary"}`)
+}
@@ -1,39 +1,111 @@
 package auth
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
-	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/middlewares/logger"
+	"github.com/zura-t/bookstore_fiber/service"
 	"github.com/zura-t/bookstore_fiber/token"
 )
 
-func New(log *logrus.Logger, token *token.JwtMaker) func(*fiber.Ctx) error {
+var (
+	ErrApiKeyNotAllowed = apperror.New(apperror.Forbidden, "api_key_not_allowed", "This route can't be accessed with an api key")
+	ErrMissingScope     = apperror.New(apperror.Forbidden, "missing_scope", "Api key is missing a required scope")
+)
+
+// New authenticates requests with a Bearer JWT or a personal API key, passed
+// either as a Bearer token or in the X-API-Key header. JWT sessions can access
+// every route, API keys only routes that list all of the key's required
+// scopes. Routes without scopes are not reachable with API keys. JWTs of
+// deleted users are rejected, their API keys are revoked on deletion.
+func New(log *logrus.Logger, maker *token.JwtMaker, services service.Services, scopes ...string) func(*fiber.Ctx) error {
 	return func(c *fiber.Ctx) error {
+		apiKey := c.Get("X-API-Key")
 
 		req := c.Get("Authorization")
-		if req == "" {
-			err := fmt.Errorf("Forbidden")
-			log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(err)
-			return c.Status(403).JSON(pkg.ErrorResponse(err))
+		if req == "" && apiKey == "" {
+			return apperror.ErrUnauthenticated
+		}
+
+		if apiKey == "" {
+			usertoken, ok := strings.CutPrefix(req, "Bearer ")
+			if !ok {
+				return apperror.ErrUnauthenticated
+			}
+
+			if !token.IsApiKey(usertoken) {
+				payload, err := maker.VerifyToken(usertoken)
+				if err != nil {
+					return apperror.ErrUnauthenticated.Wrap(err)
+				}
+				if err := services.Users.CheckActive(c.UserContext(), payload.UserId); err != nil {
+					if errors.Is(err, service.ErrAccountDeleted) {
+						return apperror.ErrUnauthenticated.Wrap(err)
+					}
+					return err
+				}
+
+				c.Locals("user", payload)
+				logger.AddFields(c, logrus.Fields{"user_id": payload.UserId})
+
+				return c.Next()
+			}
+			apiKey = usertoken
 		}
 
-		splitToken := strings.Split(req, "Bearer ")
-		usertoken := splitToken[1]
-		payload, err := token.VerifyToken(usertoken)
+		key, err := services.ApiKeys.Authenticate(c.UserContext(), apiKey)
 		if err != nil {
-			log.WithFields(logrus.Fields{
-				"level": "Error",
-			}).Error(err)
-			return c.Status(403).JSON(pkg.ErrorResponse(err))
+			return apperror.ErrUnauthenticated.Wrap(err)
+		}
+
+		if len(scopes) == 0 {
+			return ErrApiKeyNotAllowed
+		}
+		for _, scope := range scopes {
+			if !key.HasScope(scope) {
+				return ErrMissingScope.WithMessage(fmt.Sprintf("Api key is missing the '%s' scope", scope)).WithParams(scope)
+			}
 		}
 
-		c.Locals("user", payload)
+		if err := services.ApiKeys.MarkUsed(c.UserContext(), key); err != nil {
+			logger.Entry(c).Warn(err)
+		}
+
+		c.Locals("user", &token.Payload{
+			UserId:    key.User.Id,
+			Email:     key.User.Email,
+			IssuedAt:  key.CreatedAt,
+			ExpiredAt: expiry(key),
+		})
+		c.Locals("api_key", key)
+		logger.AddFields(c, logrus.Fields{"user_id": key.User.Id, "api_key": key.Prefix})
 
 		return c.Next()
 	}
 }
+
+// Optional authenticates the requests that carry credentials like New does
+// and lets the others through anonymously, for routes that serve both.
+func Optional(log *logrus.Logger, maker *token.JwtMaker, services service.Services, scopes ...string) func(*fiber.Ctx) error {
+	authenticate := New(log, maker, services, scopes...)
+	return func(c *fiber.Ctx) error {
+		if c.Get("Authorization") == "" && c.Get("X-API-Key") == "" {
+			return c.Next()
+		}
+		return authenticate(c)
+	}
+}
+
+func expiry(key *entity.ApiKey) time.Time {
+	if key.ExpiresAt != nil {
+		return *key.ExpiresAt
+	}
+	return time.Time{}
+}
@@ -0,0 +1,40 @@
+// Package deprecation marks responses of deprecated routes with the
+// Deprecation (RFC 9745), Sunset (RFC 8594) and successor Link headers.
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+)
+
+// New returns a group middleware for routes deprecated since deprecatedAt
+// and removed at sunset, zero times are left out. The successor of a route is
+// its path after successorPrefix, e.g. /books becomes /v1/books.
+func New(deprecatedAt, sunset time.Time, successorPrefix string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		own := c.Route()
+		err := c.Next()
+		// Requests no route of the group matched fall through this middleware
+		// too, they are not deprecated but unknown.
+		if c.Route() == own {
+			return err
+		}
+
+		if !deprecatedAt.IsZero() {
+			c.Set(HeaderDeprecation, fmt.Sprintf("@%d", deprecatedAt.Unix()))
+		}
+		if !sunset.IsZero() {
+			c.Set(HeaderSunset, sunset.UTC().Format(http.TimeFormat))
+		}
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s%s>; rel="successor-version"`, successorPrefix, c.OriginalURL()))
+		return err
+	}
+}
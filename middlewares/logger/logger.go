@@ -1,13 +1,77 @@
 package logger
 
 import (
+	"regexp"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
-	"log"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/logger"
+	"go.opentelemetry.io/otel/trace"
 )
 
-func New(c *fiber.Ctx) error {
+const HeaderRequestID = "X-Request-ID"
+
+// validRequestID keeps ids sent by clients or proxies short and printable.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// New logs one line per request with its method, route, status, latency,
+// size and user. The request ID is taken from the X-Request-ID header or
+// generated, echoed in the response, and added to every line logged through
+// Entry while the request is handled, along with the trace and span IDs when
+// a tracing middleware ran before.
+func New(log *logrus.Logger) func(*fiber.Ctx) error {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+
+		id := c.Get(HeaderRequestID)
+		if !validRequestID.MatchString(id) {
+			id = uuid.NewString()
+		}
+		c.Set(HeaderRequestID, id)
+		fields := logrus.Fields{"request_id": id}
+		if span := trace.SpanContextFromContext(c.UserContext()); span.IsValid() {
+			fields["trace_id"] = span.TraceID().String()
+			fields["span_id"] = span.SpanID().String()
+		}
+		c.SetUserContext(logger.NewContext(c.UserContext(), log.WithFields(fields)))
+
+		// Errors returned by handlers are turned into responses here, so the
+		// logged status is the one sent to the client.
+		if err := c.Next(); err != nil {
+			if err := c.App().ErrorHandler(c, err); err != nil {
+				c.Status(fiber.StatusInternalServerError)
+			}
+		}
+
+		status := c.Response().StatusCode()
+		entry := Entry(c).WithFields(logrus.Fields{
+			"method":     c.Method(),
+			"route":      c.Route().Path,
+			"status":     status,
+			"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			"bytes":      len(c.Response().Body()),
+		})
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			entry.Error("request")
+		case status >= fiber.StatusBadRequest:
+			entry.Warn("request")
+		default:
+			entry.Info("request")
+		}
+		return nil
+	}
+}
 
-		log.Print("logger middleware enabled")
+// Entry returns the logger of the request.
+func Entry(c *fiber.Ctx) *logrus.Entry {
+	return logger.FromContext(c.UserContext(), logrus.StandardLogger())
+}
 
-		return c.Next()
-}
\ No newline at end of file
+// AddFields adds fields to every line logged through Entry for the rest of
+// the request, e.g. the user once it is authenticated.
+func AddFields(c *fiber.Ctx, fields logrus.Fields) {
+	c.SetUserContext(logger.NewContext(c.UserContext(), Entry(c).WithFields(fields)))
+}
@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var out bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&out)
+	log.SetFormatter(&logrus.JSONFormatter{})
+
+	app := fiber.New()
+	app.Use(New(log))
+	app.Get("/books/:id", func(c *fiber.Ctx) error {
+		AddFields(c, logrus.Fields{"user_id": 7})
+		Entry(c).Error("book not found")
+		return c.Status(fiber.StatusNotFound).SendString("not found")
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return errors.New("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	req.Header.Set(HeaderRequestID, "req-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, "req-1", resp.Header.Get(HeaderRequestID))
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/fail", nil))
+	require.NoError(t, err)
+	require.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	generated := resp.Header.Get(HeaderRequestID)
+	require.NotEmpty(t, generated)
+
+	var lines []map[string]interface{}
+	scanner := bufio.NewScanner(&out)
+	for scanner.Scan() {
+		var line map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 3)
+
+	require.Equal(t, "book not found", lines[0]["msg"])
+	require.Equal(t, "req-1", lines[0]["request_id"])
+	require.EqualValues(t, 7, lines[0]["user_id"])
+
+	require.Equal(t, "warning", lines[1]["level"])
+	require.Equal(t, "req-1", lines[1]["request_id"])
+	require.Equal(t, "/books/:id", lines[1]["route"])
+	require.EqualValues(t, 404, lines[1]["status"])
+	require.EqualValues(t, 7, lines[1]["user_id"])
+	require.EqualValues(t, len("not found"), lines[1]["bytes"])
+
+	require.Equal(t, "error", lines[2]["level"])
+	require.Equal(t, generated, lines[2]["request_id"])
+	require.EqualValues(t, 500, lines[2]["status"])
+}
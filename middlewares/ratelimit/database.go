@@ -0,0 +1,70 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DatabaseStore keeps buckets in the rate_limit_buckets table, so that
+// replicas sharing the database share the limits.
+type DatabaseStore struct {
+	db        *gorm.DB
+	mu        sync.Mutex
+	nextSweep time.Time
+}
+
+func NewDatabaseStore(db *gorm.DB) *DatabaseStore {
+	return &DatabaseStore{db: db}
+}
+
+func (s *DatabaseStore) Update(ctx context.Context, key string, fn func(b *Bucket) time.Time) error {
+	if err := s.sweep(ctx); err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The bucket is created before it is read, so that the row is always
+		// there to be locked. SQLite has no row locks, the insert takes its
+		// write lock instead. A refill time in the past makes the bucket full.
+		epoch := time.Unix(0, 0).UTC()
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&models.RateLimitBucket{ID: key, RefilledAt: epoch, ExpiresAt: epoch}).Error
+		if err != nil {
+			return err
+		}
+
+		var row models.RateLimitBucket
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", key).Take(&row).Error
+		if err != nil {
+			return err
+		}
+
+		b := Bucket{Tokens: row.Tokens, RefilledAt: row.RefilledAt}
+		expiresAt := fn(&b)
+		return tx.Model(&row).Updates(map[string]interface{}{
+			"tokens":      b.Tokens,
+			"refilled_at": b.RefilledAt,
+			"expires_at":  expiresAt,
+		}).Error
+	})
+}
+
+// sweep deletes the buckets that are full again, at most once per
+// sweepInterval and replica.
+func (s *DatabaseStore) sweep(ctx context.Context) error {
+	now := time.Now()
+	s.mu.Lock()
+	if now.Before(s.nextSweep) {
+		s.mu.Unlock()
+		return nil
+	}
+	s.nextSweep = now.Add(sweepInterval)
+	s.mu.Unlock()
+
+	return s.db.WithContext(ctx).Where("expires_at < ?", now).Delete(&models.RateLimitBucket{}).Error
+}
@@ -0,0 +1,47 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often stores delete the buckets that are full again.
+const sweepInterval = time.Minute
+
+type memoryBucket struct {
+	Bucket
+	expiresAt time.Time
+}
+
+// MemoryStore keeps buckets in the process, so every replica has limits of
+// its own.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]memoryBucket
+	nextSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: make(map[string]memoryBucket)}
+}
+
+func (s *MemoryStore) Update(ctx context.Context, key string, fn func(b *Bucket) time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.After(s.nextSweep) {
+		for k, b := range s.buckets {
+			if now.After(b.expiresAt) {
+				delete(s.buckets, k)
+			}
+		}
+		s.nextSweep = now.Add(sweepInterval)
+	}
+
+	b := s.buckets[key]
+	b.expiresAt = fn(&b.Bucket)
+	s.buckets[key] = b
+	return nil
+}
@@ -0,0 +1,153 @@
+// Package ratelimit limits how often clients can call the API. Every client
+// has a token bucket per policy: a request takes one token and the bucket
+// refills continuously, so a client can burst up to the limit and then go on
+// at the refill rate.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/middlewares/logger"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+// Headers from the IETF RateLimit header fields draft.
+const (
+	HeaderLimit     = "RateLimit-Limit"
+	HeaderRemaining = "RateLimit-Remaining"
+	HeaderReset     = "RateLimit-Reset"
+	HeaderPolicy    = "RateLimit-Policy"
+)
+
+var ErrRateLimited = apperror.New(apperror.TooManyRequests, "rate_limited", "Too many requests")
+
+// Bucket is the state of one token bucket.
+type Bucket struct {
+	Tokens     float64
+	RefilledAt time.Time
+}
+
+// take refills b up to now and removes one token if there is one. A bucket
+// that was never used is full.
+func (b *Bucket) take(rate config.Rate, now time.Time) bool {
+	if elapsed := now.Sub(b.RefilledAt); elapsed > 0 {
+		b.Tokens = math.Min(float64(rate.Limit), b.Tokens+elapsed.Seconds()*perSecond(rate))
+		b.RefilledAt = now
+	}
+	if b.Tokens < 1 {
+		return false
+	}
+	b.Tokens--
+	return true
+}
+
+// until returns how long it takes b to hold tokens.
+func (b Bucket) until(rate config.Rate, tokens float64) time.Duration {
+	if b.Tokens >= tokens {
+		return 0
+	}
+	return time.Duration((tokens - b.Tokens) / perSecond(rate) * float64(time.Second))
+}
+
+func perSecond(rate config.Rate) float64 {
+	return float64(rate.Limit) / rate.Period.Seconds()
+}
+
+// Store keeps the buckets. Updates of one bucket must not interleave, so that
+// concurrent requests, possibly on other replicas, can't spend the same token.
+type Store interface {
+	// Update calls fn with the bucket of key, a zero Bucket when there is
+	// none, and saves the result. fn returns when the bucket is full again,
+	// from then on the store may forget it.
+	Update(ctx context.Context, key string, fn func(b *Bucket) time.Time) error
+}
+
+// KeyFunc identifies the client a request is counted for.
+type KeyFunc func(c *fiber.Ctx) string
+
+func ByIP(c *fiber.Ctx) string {
+	return "ip:" + c.IP()
+}
+
+// ByClient counts requests with a valid access token per user and requests
+// with a valid API key per key, anything else per IP. It runs before the auth
+// middleware, so it looks API keys up itself: made up keys must not get
+// buckets of their own, or rotating them would bypass every limit.
+func ByClient(maker *token.JwtMaker, apiKeys *service.ApiKeyService) KeyFunc {
+	return func(c *fiber.Ctx) string {
+		credential := c.Get("X-API-Key")
+		if credential == "" {
+			credential, _ = strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		}
+		if credential == "" {
+			return ByIP(c)
+		}
+
+		if token.IsApiKey(credential) {
+			if key, err := apiKeys.Authenticate(c.UserContext(), credential); err == nil {
+				return "key:" + key.Prefix
+			}
+		} else if payload, err := maker.VerifyToken(credential); err == nil {
+			return fmt.Sprintf("user:%d", payload.UserId)
+		}
+		return ByIP(c)
+	}
+}
+
+type Limiter struct {
+	store Store
+	key   KeyFunc
+	now   func() time.Time
+}
+
+func New(store Store, key KeyFunc) *Limiter {
+	return &Limiter{store, key, time.Now}
+}
+
+// Handler limits requests to rate under the policy name, which keeps its
+// buckets apart from the ones of other policies. When the store fails the
+// request is let through, an unavailable store should not take the API down.
+func (l *Limiter) Handler(name string, rate config.Rate) fiber.Handler {
+	policy := fmt.Sprintf("%d;w=%d", rate.Limit, int(math.Ceil(rate.Period.Seconds())))
+
+	return func(c *fiber.Ctx) error {
+		var bucket Bucket
+		var allowed bool
+		now := l.now()
+		err := l.store.Update(c.UserContext(), name+":"+l.key(c), func(b *Bucket) time.Time {
+			allowed = b.take(rate, now)
+			bucket = *b
+			return now.Add(b.until(rate, float64(rate.Limit)))
+		})
+		if err != nil {
+			logger.Entry(c).WithField("policy", name).Warn(err)
+			return c.Next()
+		}
+
+		c.Set(HeaderLimit, strconv.Itoa(rate.Limit))
+		c.Set(HeaderRemaining, strconv.Itoa(int(bucket.Tokens)))
+		c.Set(HeaderReset, seconds(bucket.until(rate, float64(rate.Limit))))
+		c.Set(HeaderPolicy, policy)
+
+		if !allowed {
+			retry := seconds(bucket.until(rate, 1))
+			c.Set(fiber.HeaderRetryAfter, retry)
+			return ErrRateLimited.WithMessage(fmt.Sprintf("Too many requests, try again in %s seconds", retry)).WithParams(retry)
+		}
+		return c.Next()
+	}
+}
+
+// seconds rounds d up, so that clients waiting that long find a token.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,125 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/problem"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/migrations"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+	"gorm.io/gorm"
+)
+
+func databaseStore(t *testing.T) Store {
+	dsn := fmt.Sprintf("file:%s?mode=memory&cache=shared", pkg.RandomString(16))
+	db, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{})
+	require.NoError(t, err)
+	migrator, err := migrations.New(db)
+	require.NoError(t, err)
+	_, err = migrator.Up(context.Background())
+	require.NoError(t, err)
+	return NewDatabaseStore(db)
+}
+
+func TestLimiter(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"memory":   func(*testing.T) Store { return NewMemoryStore() },
+		"database": databaseStore,
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			now := time.Now()
+			limiter := New(store(t), func(c *fiber.Ctx) string { return c.Get("X-Client") })
+			limiter.now = func() time.Time { return now }
+
+			app := fiber.New(fiber.Config{ErrorHandler: problem.ErrorHandler})
+			app.Use(limiter.Handler("default", config.Rate{Limit: 2, Period: time.Minute}))
+			app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(http.StatusOK) })
+
+			request := func(client string) *http.Response {
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("X-Client", client)
+				resp, err := app.Test(req)
+				require.NoError(t, err)
+				return resp
+			}
+
+			resp := request("ann")
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Equal(t, "2", resp.Header.Get(HeaderLimit))
+			require.Equal(t, "1", resp.Header.Get(HeaderRemaining))
+			require.Equal(t, "30", resp.Header.Get(HeaderReset))
+			require.Equal(t, "2;w=60", resp.Header.Get(HeaderPolicy))
+
+			require.Equal(t, http.StatusOK, request("ann").StatusCode)
+			resp = request("ann")
+			require.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+			require.Equal(t, "0", resp.Header.Get(HeaderRemaining))
+			require.Equal(t, "30", resp.Header.Get(fiber.HeaderRetryAfter))
+
+			require.Equal(t, http.StatusOK, request("bob").StatusCode)
+
+			now = now.Add(30 * time.Second)
+			require.Equal(t, http.StatusOK, request("ann").StatusCode)
+			require.Equal(t, http.StatusTooManyRequests, request("ann").StatusCode)
+		})
+	}
+}
+
+func TestByClient(t *testing.T) {
+	maker, err := token.NewJwtMaker(logrus.New(), pkg.RandomString(32))
+	require.NoError(t, err)
+	access, _, err := maker.CreateToken(7, "ann@example.com", time.Minute)
+	require.NoError(t, err)
+	hasher := pkg.NewPasswordHasher(pkg.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1})
+	services := service.New(logrus.New(), config.Config{}, repository.NewMemoryRepositories(), hasher, pkg.NewPasswordPolicy(8, 64), nil)
+	key, apiKey, err := services.ApiKeys.Create(context.Background(), 7, "ci", nil, 0)
+	require.NoError(t, err)
+	unknownKey, _, _, err := token.GenerateApiKey()
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString(ByClient(maker, services.ApiKeys)(c)) })
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+		key    string
+	}{
+		{"anonymous", "", "", "ip:0.0.0.0"},
+		{"access token", fiber.HeaderAuthorization, "Bearer " + access, "user:7"},
+		{"invalid access token", fiber.HeaderAuthorization, "Bearer invalid", "ip:0.0.0.0"},
+		{"api key", "X-API-Key", apiKey, "key:" + key.Prefix},
+		{"bearer api key", fiber.HeaderAuthorization, "Bearer " + apiKey, "key:" + key.Prefix},
+		{"unknown api key", "X-API-Key", unknownKey, "ip:0.0.0.0"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Equal(t, tc.key, string(body))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestLoadEmbedded(t *testing.T) {
+	postgres, err := load(files, "postgres")
+	require.NoError(t, err)
+	require.NotEmpty(t, postgres)
+
+	for i, m := range postgres {
+		require.Equal(t, int64(i+1), m.Version)
+		require.NotEmpty(t, m.Up)
+		require.NotEmpty(t, m.Down)
+	}
+
+	for _, dialect := range []string{"mysql", "sqlite"} {
+		migrations, err := load(files, dialect)
+		require.NoError(t, err)
+		require.Len(t, migrations, len(postgres), dialect)
+		for i, m := range migrations {
+			require.Equal(t, postgres[i].Version, m.Version, dialect)
+			require.Equal(t, postgres[i].Name, m.Name, dialect)
+		}
+	}
+}
+
+func TestSqliteUpAndDown(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open("file:migrations?mode=memory&cache=shared&_pragma=foreign_keys(1)"), &gorm.Config{})
+	require.NoError(t, err)
+
+	migrator, err := New(db)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, len(migrator.migrations))
+	require.NoError(t, migrator.Check(ctx))
+
+	reverted, err := migrator.Down(ctx, len(applied))
+	require.NoError(t, err)
+	require.Len(t, reverted, len(applied))
+
+	pending, err := migrator.Pending(ctx)
+	require.NoError(t, err)
+	require.Len(t, pending, len(applied))
+}
+
+func TestCheckIsReadOnly(t *testing.T) {
+	ctx := context.Background()
+	db, err := gorm.Open(sqlite.Open("file:check?mode=memory&cache=shared"), &gorm.Config{})
+	require.NoError(t, err)
+
+	migrator, err := New(db)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, migrator.Check(ctx), ErrPendingMigrations)
+	require.False(t, db.Migrator().HasTable(&schemaMigration{}))
+}
+
+func TestLoadOrdersAndPairsFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0010_b.up.sql":   {Data: []byte("up b")},
+		"sql/0010_b.down.sql": {Data: []byte("down b")},
+		"sql/0002_a.up.sql":   {Data: []byte("up a")},
+		"sql/0002_a.down.sql": {Data: []byte("down a")},
+		"sql/README.md":       {Data: []byte("ignored")},
+	}
+
+	migrations, err := load(fsys, "sql")
+	require.NoError(t, err)
+	require.Len(t, migrations, 2)
+	require.Equal(t, Migration{Version: 2, Name: "a", Up: "up a", Down: "down a"}, migrations[0])
+	require.Equal(t, Migration{Version: 10, Name: "b", Up: "up b", Down: "down b"}, migrations[1])
+
+	delete(fsys, "sql/0010_b.down.sql")
+	_, err = load(fsys, "sql")
+	require.Error(t, err)
+}
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS user_books;
+DROP TABLE IF EXISTS books;
+DROP TABLE IF EXISTS users;
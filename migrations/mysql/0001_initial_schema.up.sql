@@ -0,0 +1,46 @@
+CREATE TABLE users (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    name VARCHAR(255),
+    email VARCHAR(255),
+    password VARCHAR(255),
+    is_author BOOLEAN DEFAULT false
+);
+
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+
+CREATE TABLE books (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    title VARCHAR(255),
+    description TEXT,
+    price BIGINT UNSIGNED,
+    author_id BIGINT UNSIGNED,
+    file VARCHAR(1024),
+    CONSTRAINT fk_users_author_books FOREIGN KEY (author_id) REFERENCES users (id)
+);
+
+-- The explicit user_id indexes keep the foreign keys from taking over the
+-- unique indexes added later, which could then not be dropped.
+CREATE TABLE user_books (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME(3),
+    user_id BIGINT UNSIGNED NOT NULL,
+    book_id BIGINT UNSIGNED NOT NULL,
+    INDEX idx_user_books_user_id (user_id),
+    CONSTRAINT fk_user_books_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_books_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+
+CREATE TABLE cart_items (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT UNSIGNED,
+    book_id BIGINT UNSIGNED,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    INDEX idx_cart_items_user_id (user_id),
+    CONSTRAINT fk_cart_items_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_cart_items_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
@@ -0,0 +1,22 @@
+CREATE TABLE user_identities (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    user_id BIGINT UNSIGNED,
+    provider VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    CONSTRAINT fk_user_identities_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+CREATE UNIQUE INDEX idx_identity_provider_subject ON user_identities (provider, subject);
+
+CREATE TABLE oidc_states (
+    state VARCHAR(255) PRIMARY KEY,
+    created_at DATETIME(3),
+    provider VARCHAR(255) NOT NULL,
+    code_verifier VARCHAR(255) NOT NULL,
+    nonce VARCHAR(255) NOT NULL,
+    expires_at DATETIME(3) NOT NULL
+);
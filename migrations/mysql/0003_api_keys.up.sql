@@ -0,0 +1,17 @@
+CREATE TABLE api_keys (
+    id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    user_id BIGINT UNSIGNED,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(255) NOT NULL,
+    hash VARCHAR(255) NOT NULL,
+    scopes VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at DATETIME(3),
+    last_used_at DATETIME(3),
+    revoked_at DATETIME(3),
+    CONSTRAINT fk_api_keys_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
+CREATE UNIQUE INDEX idx_api_keys_prefix ON api_keys (prefix);
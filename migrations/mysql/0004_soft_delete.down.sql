@@ -0,0 +1,6 @@
+DROP INDEX idx_books_deleted_at ON books;
+ALTER TABLE books DROP COLUMN deleted_at;
+
+DROP INDEX idx_users_deleted_at ON users;
+ALTER TABLE users DROP COLUMN anonymized_at;
+ALTER TABLE users DROP COLUMN deleted_at;
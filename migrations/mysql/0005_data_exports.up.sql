@@ -0,0 +1,13 @@
+CREATE TABLE data_exports (
+    id VARCHAR(36) PRIMARY KEY,
+    created_at DATETIME(3),
+    updated_at DATETIME(3),
+    user_id BIGINT UNSIGNED,
+    status VARCHAR(32) NOT NULL,
+    file VARCHAR(1024) NOT NULL DEFAULT '',
+    error VARCHAR(255) NOT NULL DEFAULT '',
+    expires_at DATETIME(3),
+    CONSTRAINT fk_data_exports_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports (user_id);
@@ -0,0 +1,2 @@
+DROP INDEX idx_cart_items_user_book ON cart_items;
+DROP INDEX idx_user_books_user_book ON user_books;
@@ -0,0 +1,12 @@
+-- Remove duplicates the missing constraints allowed, keeping the oldest row.
+-- MySQL can't select from the table it deletes from, hence the derived table.
+DELETE FROM user_books WHERE id NOT IN (
+    SELECT id FROM (SELECT MIN(id) AS id FROM user_books GROUP BY user_id, book_id) AS keep
+);
+
+DELETE FROM cart_items WHERE id NOT IN (
+    SELECT id FROM (SELECT MIN(id) AS id FROM cart_items GROUP BY user_id, book_id) AS keep
+);
+
+CREATE UNIQUE INDEX idx_user_books_user_book ON user_books (user_id, book_id);
+CREATE UNIQUE INDEX idx_cart_items_user_book ON cart_items (user_id, book_id);
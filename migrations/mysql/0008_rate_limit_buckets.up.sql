@@ -0,0 +1,8 @@
+CREATE TABLE rate_limit_buckets (
+    id VARCHAR(191) PRIMARY KEY,
+    tokens DOUBLE NOT NULL,
+    refilled_at DATETIME(3) NOT NULL,
+    expires_at DATETIME(3) NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_expires_at ON rate_limit_buckets (expires_at);
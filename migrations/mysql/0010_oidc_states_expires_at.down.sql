@@ -0,0 +1 @@
+DROP INDEX idx_oidc_states_expires_at ON oidc_states;
@@ -0,0 +1 @@
+CREATE INDEX idx_oidc_states_expires_at ON oidc_states (expires_at);
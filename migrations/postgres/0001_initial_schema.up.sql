@@ -0,0 +1,45 @@
+-- Tables created by the former AutoMigrate call. IF NOT EXISTS lets databases
+-- that were set up by AutoMigrate adopt the versioned migrations.
+CREATE TABLE IF NOT EXISTS users (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    name TEXT,
+    email TEXT,
+    password TEXT,
+    is_author BOOLEAN DEFAULT false
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+
+CREATE TABLE IF NOT EXISTS books (
+    id BIGSERIAL PRIMARY KEY,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    title TEXT,
+    description TEXT,
+    price BIGINT,
+    author_id BIGINT,
+    file TEXT,
+    CONSTRAINT fk_users_author_books FOREIGN KEY (author_id) REFERENCES users (id)
+);
+
+CREATE TABLE IF NOT EXISTS user_books (
+    id BIGSERIAL,
+    created_at TIMESTAMPTZ,
+    user_id BIGINT,
+    book_id BIGINT,
+    PRIMARY KEY (id, user_id, book_id),
+    CONSTRAINT fk_user_books_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_books_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+
+CREATE TABLE IF NOT EXISTS cart_items (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT,
+    book_id BIGINT,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT fk_cart_items_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_cart_items_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_cart_items_user_book;
+DROP INDEX IF EXISTS idx_user_books_user_book;
@@ -0,0 +1,9 @@
+-- Remove duplicates the missing constraints allowed, keeping the oldest row.
+DELETE FROM user_books a USING user_books b
+WHERE a.user_id = b.user_id AND a.book_id = b.book_id AND a.id > b.id;
+
+DELETE FROM cart_items a USING cart_items b
+WHERE a.user_id = b.user_id AND a.book_id = b.book_id AND a.id > b.id;
+
+CREATE UNIQUE INDEX idx_user_books_user_book ON user_books (user_id, book_id);
+CREATE UNIQUE INDEX idx_cart_items_user_book ON cart_items (user_id, book_id);
@@ -0,0 +1 @@
+ALTER TABLE oidc_states ADD COLUMN IF NOT EXISTS restore BOOLEAN NOT NULL DEFAULT FALSE;
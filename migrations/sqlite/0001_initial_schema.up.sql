@@ -0,0 +1,43 @@
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    name TEXT,
+    email TEXT,
+    password TEXT,
+    is_author BOOLEAN DEFAULT false
+);
+
+CREATE UNIQUE INDEX idx_users_email ON users (email);
+
+CREATE TABLE books (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    title TEXT,
+    description TEXT,
+    price INTEGER,
+    author_id INTEGER,
+    file TEXT,
+    CONSTRAINT fk_users_author_books FOREIGN KEY (author_id) REFERENCES users (id)
+);
+
+-- SQLite only auto increments a single column primary key.
+CREATE TABLE user_books (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    user_id INTEGER NOT NULL,
+    book_id INTEGER NOT NULL,
+    CONSTRAINT fk_user_books_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_books_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
+
+CREATE TABLE cart_items (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER,
+    book_id INTEGER,
+    created_at DATETIME,
+    updated_at DATETIME,
+    CONSTRAINT fk_cart_items_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_cart_items_book FOREIGN KEY (book_id) REFERENCES books (id)
+);
@@ -0,0 +1,21 @@
+CREATE TABLE user_identities (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    user_id INTEGER REFERENCES users (id),
+    provider TEXT NOT NULL,
+    subject TEXT NOT NULL,
+    email TEXT
+);
+
+CREATE INDEX idx_user_identities_user_id ON user_identities (user_id);
+CREATE UNIQUE INDEX idx_identity_provider_subject ON user_identities (provider, subject);
+
+CREATE TABLE oidc_states (
+    state TEXT PRIMARY KEY,
+    created_at DATETIME,
+    provider TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    nonce TEXT NOT NULL,
+    expires_at DATETIME NOT NULL
+);
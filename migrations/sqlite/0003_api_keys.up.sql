@@ -0,0 +1,16 @@
+CREATE TABLE api_keys (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    created_at DATETIME,
+    updated_at DATETIME,
+    user_id INTEGER REFERENCES users (id),
+    name TEXT NOT NULL,
+    prefix TEXT NOT NULL,
+    hash TEXT NOT NULL,
+    scopes TEXT NOT NULL DEFAULT '',
+    expires_at DATETIME,
+    last_used_at DATETIME,
+    revoked_at DATETIME
+);
+
+CREATE INDEX idx_api_keys_user_id ON api_keys (user_id);
+CREATE UNIQUE INDEX idx_api_keys_prefix ON api_keys (prefix);
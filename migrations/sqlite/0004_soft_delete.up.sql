@@ -0,0 +1,6 @@
+ALTER TABLE users ADD COLUMN deleted_at DATETIME;
+ALTER TABLE users ADD COLUMN anonymized_at DATETIME;
+CREATE INDEX idx_users_deleted_at ON users (deleted_at);
+
+ALTER TABLE books ADD COLUMN deleted_at DATETIME;
+CREATE INDEX idx_books_deleted_at ON books (deleted_at);
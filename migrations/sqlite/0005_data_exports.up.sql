@@ -0,0 +1,12 @@
+CREATE TABLE data_exports (
+    id TEXT PRIMARY KEY,
+    created_at DATETIME,
+    updated_at DATETIME,
+    user_id INTEGER REFERENCES users (id),
+    status TEXT NOT NULL,
+    file TEXT NOT NULL DEFAULT '',
+    error TEXT NOT NULL DEFAULT '',
+    expires_at DATETIME
+);
+
+CREATE INDEX idx_data_exports_user_id ON data_exports (user_id);
@@ -0,0 +1,11 @@
+-- Remove duplicates the missing constraints allowed, keeping the oldest row.
+DELETE FROM user_books WHERE id NOT IN (
+    SELECT MIN(id) FROM user_books GROUP BY user_id, book_id
+);
+
+DELETE FROM cart_items WHERE id NOT IN (
+    SELECT MIN(id) FROM cart_items GROUP BY user_id, book_id
+);
+
+CREATE UNIQUE INDEX idx_user_books_user_book ON user_books (user_id, book_id);
+CREATE UNIQUE INDEX idx_cart_items_user_book ON cart_items (user_id, book_id);
@@ -0,0 +1,8 @@
+CREATE TABLE rate_limit_buckets (
+    id TEXT PRIMARY KEY,
+    tokens REAL NOT NULL,
+    refilled_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_rate_limit_buckets_expires_at ON rate_limit_buckets (expires_at);
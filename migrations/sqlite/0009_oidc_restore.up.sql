@@ -0,0 +1 @@
+ALTER TABLE oidc_states ADD COLUMN restore BOOLEAN NOT NULL DEFAULT false;
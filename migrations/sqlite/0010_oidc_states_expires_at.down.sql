@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_oidc_states_expires_at;
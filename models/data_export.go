@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+const (
+	ExportPending = "pending"
+	ExportReady   = "ready"
+	ExportFailed  = "failed"
+	ExportExpired = "expired"
+)
+
+type DataExport struct {
+	ID        string     `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	UserID    uint       `gorm:"index" json:"user_id"`
+	Status    string     `json:"status"`
+	File      string     `json:"-"`
+	Error     string     `json:"error"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
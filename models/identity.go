@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+type UserIdentity struct {
+	ID        uint      `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	UserID    uint      `gorm:"index" json:"user_id"`
+	User      User      `json:"user"`
+	Provider  string    `gorm:"uniqueIndex:idx_identity_provider_subject" json:"provider"`
+	Subject   string    `gorm:"uniqueIndex:idx_identity_provider_subject" json:"subject"`
+	Email     string    `json:"email"`
+}
+
+type OidcState struct {
+	State        string    `gorm:"primarykey" json:"state"`
+	CreatedAt    time.Time `json:"created_at"`
+	Provider     string    `json:"provider"`
+	CodeVerifier string    `json:"code_verifier"`
+	Nonce        string    `json:"nonce"`
+	ExpiresAt    time.Time `gorm:"index" json:"expires_at"`
+	Restore      bool      `json:"restore"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// RateLimitBucket is the token bucket of one client under one rate limit
+// policy. A bucket is full again at ExpiresAt and can then be deleted.
+type RateLimitBucket struct {
+	ID         string `gorm:"primarykey"`
+	Tokens     float64
+	RefilledAt time.Time
+	ExpiresAt  time.Time `gorm:"index"`
+}
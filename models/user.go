@@ -1,15 +1,22 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type User struct {
-	ID            uint      `gorm:"primarykey" json:"id"`
-	CreatedAt     time.Time `json:"created_at"`
-	UpdatedAt     time.Time `json:"updated_at"`
-	Name          string    `json:"name"`
-	Email         string    `gorm:"uniqueIndex" json:"email"`
-	Password      string    `json:"password"`
-	IsAuthor      bool      `gorm:"default:false" json:"is_author"`
-	ReadList      []Book    `gorm:"many2many:user_books;" json:"read_list"`
-	AuthorBooks   []Book    `gorm:"foreignKey:AuthorID" json:"author_books"`
+	ID           uint           `gorm:"primarykey" json:"id"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	AnonymizedAt *time.Time     `json:"anonymized_at"`
+	Name         string         `json:"name"`
+	Email        string         `gorm:"uniqueIndex" json:"email"`
+	Password     string         `json:"password"`
+	IsAuthor     bool           `gorm:"default:false" json:"is_author"`
+	Locale       string         `json:"locale"`
+	ReadList     []Book         `gorm:"many2many:user_books;" json:"read_list"`
+	AuthorBooks  []Book         `gorm:"foreignKey:AuthorID" json:"author_books"`
 }
@@ -0,0 +1,163 @@
+package oidc_test
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/oidc"
+	"github.com/zura-t/bookstore_fiber/oidc/oidctest"
+	"github.com/zura-t/bookstore_fiber/pkg"
+)
+
+func authorize(t *testing.T, provider *oidc.Provider, state, nonce, verifier string) url.Values {
+	authURL, err := provider.AuthCodeURL(context.Background(), state, nonce, verifier)
+	require.NoError(t, err)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	return location.Query()
+}
+
+func TestProviderFlow(t *testing.T) {
+	mock := oidctest.NewProvider()
+	defer mock.Close()
+
+	user := oidctest.User{
+		Subject:       pkg.RandomString(12),
+		Email:         pkg.RandomEmail(),
+		EmailVerified: true,
+		Name:          pkg.RandomString(6),
+	}
+	mock.SetUser(user)
+
+	provider := oidc.NewProvider(oidc.ProviderConfig{
+		Name:        "mock",
+		Issuer:      mock.Issuer(),
+		ClientID:    "bookstore",
+		RedirectURL: "http://localhost/auth/mock/callback",
+	}, nil)
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+
+	query := authorize(t, provider, "state-1", "nonce-1", verifier)
+	require.Equal(t, "state-1", query.Get("state"))
+
+	tokens, err := provider.Exchange(context.Background(), query.Get("code"), verifier)
+	require.NoError(t, err)
+
+	claims, err := provider.VerifyIDToken(context.Background(), tokens.IDToken, "nonce-1")
+	require.NoError(t, err)
+	require.Equal(t, user.Subject, claims.Subject)
+	require.Equal(t, user.Email, claims.Email)
+	require.True(t, claims.EmailVerified)
+	require.Equal(t, user.Name, claims.Name)
+
+	_, err = provider.VerifyIDToken(context.Background(), tokens.IDToken, "other-nonce")
+	require.ErrorIs(t, err, oidc.ErrNonceMismatch)
+
+	query = authorize(t, provider, "state-2", "nonce-2", verifier)
+	otherVerifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+	_, err = provider.Exchange(context.Background(), query.Get("code"), otherVerifier)
+	require.Error(t, err)
+}
+
+// countingTransport counts the requests to paths ending with suffix.
+type countingTransport struct {
+	suffix string
+	count  int
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasSuffix(req.URL.Path, c.suffix) {
+		c.count++
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestProviderLimitsKeyRefresh(t *testing.T) {
+	mock := oidctest.NewProvider()
+	defer mock.Close()
+	mock.SetUser(oidctest.User{Subject: pkg.RandomString(12)})
+
+	jwks := &countingTransport{suffix: "/jwks"}
+	provider := oidc.NewProvider(oidc.ProviderConfig{
+		Name:        "mock",
+		Issuer:      mock.Issuer(),
+		ClientID:    "bookstore",
+		RedirectURL: "http://localhost/auth/mock/callback",
+	}, &http.Client{Transport: jwks})
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+	query := authorize(t, provider, "state", "nonce", verifier)
+	tokens, err := provider.Exchange(context.Background(), query.Get("code"), verifier)
+	require.NoError(t, err)
+	_, err = provider.VerifyIDToken(context.Background(), tokens.IDToken, "nonce")
+	require.NoError(t, err)
+	require.Equal(t, 1, jwks.count)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		forged := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":   mock.Issuer(),
+			"aud":   "bookstore",
+			"sub":   "forged",
+			"nonce": "nonce",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+		})
+		forged.Header["kid"] = pkg.RandomString(8)
+		raw, err := forged.SignedString(key)
+		require.NoError(t, err)
+
+		_, err = provider.VerifyIDToken(context.Background(), raw, "nonce")
+		require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+	}
+	require.Equal(t, 1, jwks.count)
+}
+
+func TestProviderRejectsForeignAudience(t *testing.T) {
+	mock := oidctest.NewProvider()
+	defer mock.Close()
+	mock.SetUser(oidctest.User{Subject: pkg.RandomString(12)})
+
+	issuing := oidc.NewProvider(oidc.ProviderConfig{
+		Name:        "mock",
+		Issuer:      mock.Issuer(),
+		ClientID:    "another-client",
+		RedirectURL: "http://localhost/callback",
+	}, nil)
+	verifying := oidc.NewProvider(oidc.ProviderConfig{
+		Name:     "mock",
+		Issuer:   mock.Issuer(),
+		ClientID: "bookstore",
+	}, nil)
+
+	verifier, err := oidc.NewCodeVerifier()
+	require.NoError(t, err)
+
+	query := authorize(t, issuing, "state", "nonce", verifier)
+	tokens, err := issuing.Exchange(context.Background(), query.Get("code"), verifier)
+	require.NoError(t, err)
+
+	_, err = verifying.VerifyIDToken(context.Background(), tokens.IDToken, "nonce")
+	require.ErrorIs(t, err, oidc.ErrInvalidIDToken)
+}
@@ -0,0 +1,59 @@
+package pkg
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/zura-t/bookstore_fiber/apperror"
+)
+
+type sources struct {
+	params, query, body bool
+}
+
+// boundSources caches the sources of every request type Bind has seen.
+var boundSources sync.Map
+
+// Bind fills req, a pointer to a struct, from the route parameters, query
+// string and body and validates it. A source is only read when a field is
+// tagged for it: "params", "query", or "json" and "form" for the body, so
+// e.g. a query string can't set body fields. Fields set before the call are
+// the defaults.
+func Bind(c *fiber.Ctx, req interface{}) error {
+	src := sourcesOf(reflect.TypeOf(req).Elem())
+
+	if src.params {
+		if err := c.ParamsParser(req); err != nil {
+			return apperror.ErrInvalidParams.Wrap(err)
+		}
+	}
+	if src.query {
+		if err := c.QueryParser(req); err != nil {
+			return apperror.ErrInvalidParams.Wrap(err)
+		}
+	}
+	if src.body && (len(c.Body()) > 0 || len(c.Request().Header.ContentType()) > 0) {
+		if err := c.BodyParser(req); err != nil {
+			return apperror.ErrInvalidBody.Wrap(err)
+		}
+	}
+
+	return Validate(req)
+}
+
+func sourcesOf(t reflect.Type) sources {
+	if cached, ok := boundSources.Load(t); ok {
+		return cached.(sources)
+	}
+
+	var src sources
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag
+		src.params = src.params || tag.Get("params") != ""
+		src.query = src.query || tag.Get("query") != ""
+		src.body = src.body || tag.Get("json") != "" || tag.Get("form") != ""
+	}
+	boundSources.Store(t, src)
+	return src
+}
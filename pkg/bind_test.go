@@ -0,0 +1,76 @@
+package pkg
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/apperror"
+)
+
+type bindRequest struct {
+	Id    uint     `params:"id" validate:"required,min=1"`
+	Limit int      `query:"limit" validate:"max=50"`
+	Slug  string   `json:"slug" validate:"required,slug"`
+	Isbn  string   `json:"isbn" validate:"omitempty,isbn"`
+	Price uint     `json:"price" validate:"price"`
+	Tags  []string `json:"tags" validate:"max=2"`
+}
+
+func TestBind(t *testing.T) {
+	app := fiber.New()
+	app.Post("/:id", func(c *fiber.Ctx) error {
+		req := &bindRequest{Limit: 10}
+		if err := Bind(c, req); err != nil {
+			var appErr *apperror.Error
+			require.True(t, errors.As(err, &appErr))
+			return c.Status(fiber.StatusBadRequest).JSON(appErr.Fields)
+		}
+		return c.JSON(req)
+	})
+
+	send := func(path, body string) *http.Response {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := send("/3?limit=20", `{"slug":"dune-messiah","isbn":"978-0-441-17271-9","price":12}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var bound bindRequest
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&bound))
+	require.Equal(t, bindRequest{Id: 3, Limit: 20, Slug: "dune-messiah", Isbn: "978-0-441-17271-9", Price: 12}, bound)
+
+	resp = send("/0?limit=100", `{"slug":"Dune Messiah","price":0}`)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	var fields []apperror.FieldError
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&fields))
+	require.Len(t, fields, 4)
+}
+
+func TestValidate(t *testing.T) {
+	err := Validate(&bindRequest{Limit: 100, Slug: "Dune Messiah", Isbn: "123", Price: MaxPrice + 1, Tags: []string{"a", "b", "c"}})
+
+	var appErr *apperror.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, apperror.ErrValidation.Code, appErr.Code)
+	messages := make(map[string]string)
+	for _, field := range appErr.Fields {
+		messages[field.Field] = field.Message
+	}
+	require.Equal(t, map[string]string{
+		"id":    "id is required",
+		"limit": "limit must be at most 50",
+		"slug":  "slug may only contain lowercase letters, digits and single dashes",
+		"isbn":  "isbn must be a valid ISBN",
+		"price": "price must be between 1 and 100000",
+		"tags":  "tags must contain at most 2 items",
+	}, messages)
+}
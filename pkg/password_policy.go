@@ -0,0 +1,117 @@
+package pkg
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	defaultPasswordMinLength = 8
+	defaultPasswordMaxLength = 128
+)
+
+// PasswordPolicy validates new passwords against length limits and a list of
+// known breached passwords.
+type PasswordPolicy struct {
+	MinLength int
+	MaxLength int
+	breached  map[string]struct{}
+}
+
+func NewPasswordPolicy(minLength, maxLength int) *PasswordPolicy {
+	if minLength <= 0 {
+		minLength = defaultPasswordMinLength
+	}
+	if maxLength <= 0 {
+		maxLength = defaultPasswordMaxLength
+	}
+	return &PasswordPolicy{
+		MinLength: minLength,
+		MaxLength: maxLength,
+		breached:  map[string]struct{}{},
+	}
+}
+
+// LoadBreachedPasswords reads a breached password list. Each line is either a
+// plain password or a SHA-1 hex digest, optionally followed by ":count" as in
+// the Have I Been Pwned downloads.
+func (p *PasswordPolicy) LoadBreachedPasswords(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("can't open breached passwords file: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if digest, _, found := strings.Cut(line, ":"); found && isSha1Hex(digest) {
+			line = digest
+		}
+		if isSha1Hex(line) {
+			p.breached[strings.ToUpper(line)] = struct{}{}
+			continue
+		}
+		p.breached[sha1Hex(line)] = struct{}{}
+	}
+	return scanner.Err()
+}
+
+const (
+	ViolationTooShort = "too_short"
+	ViolationTooLong  = "too_long"
+	ViolationBreached = "breached"
+)
+
+// PolicyViolation is returned by Validate for passwords the policy rejects.
+// Reason is one of the Violation constants, Limit the length it requires.
+type PolicyViolation struct {
+	Reason string
+	Limit  int
+}
+
+func (v PolicyViolation) Error() string {
+	switch v.Reason {
+	case ViolationTooShort:
+		return fmt.Sprintf("password must be at least %d characters", v.Limit)
+	case ViolationTooLong:
+		return fmt.Sprintf("password must be at most %d characters", v.Limit)
+	}
+	return "password has appeared in a data breach, choose another one"
+}
+
+func (p *PasswordPolicy) Validate(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return PolicyViolation{Reason: ViolationTooShort, Limit: p.MinLength}
+	}
+	if length > p.MaxLength {
+		return PolicyViolation{Reason: ViolationTooLong, Limit: p.MaxLength}
+	}
+	if _, ok := p.breached[sha1Hex(password)]; ok {
+		return PolicyViolation{Reason: ViolationBreached}
+	}
+	return nil
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func isSha1Hex(s string) bool {
+	if len(s) != sha1.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
@@ -1,6 +1,9 @@
 package pkg
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -13,16 +16,63 @@ func TestPassword(t *testing.T) {
 	hashedPassword1, err := HashPassword(password)
 	require.NoError(t, err)
 	require.NotEmpty(t, hashedPassword1)
+	require.True(t, strings.HasPrefix(hashedPassword1, "$argon2id$"))
 
 	err = CheckPassword(password, hashedPassword1)
 	require.NoError(t, err)
 
 	wrongPassword := RandomString(6)
 	err = CheckPassword(wrongPassword, hashedPassword1)
-	require.EqualError(t, err, bcrypt.ErrMismatchedHashAndPassword.Error())
+	require.EqualError(t, err, ErrMismatchedPassword.Error())
 
 	hashedPassword2, err := HashPassword(password)
 	require.NoError(t, err)
 	require.NotEmpty(t, hashedPassword2)
 	require.NotEqual(t, hashedPassword1, hashedPassword2)
-}
\ No newline at end of file
+}
+
+func TestPasswordRehash(t *testing.T) {
+	password := RandomString(8)
+
+	bcryptHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	hasher := NewPasswordHasher(Argon2Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1})
+
+	needsRehash, err := hasher.Verify(password, string(bcryptHash))
+	require.NoError(t, err)
+	require.True(t, needsRehash)
+
+	_, err = hasher.Verify(RandomString(8), string(bcryptHash))
+	require.EqualError(t, err, ErrMismatchedPassword.Error())
+
+	hashedPassword, err := hasher.Hash(password)
+	require.NoError(t, err)
+
+	needsRehash, err = hasher.Verify(password, hashedPassword)
+	require.NoError(t, err)
+	require.False(t, needsRehash)
+
+	stronger := NewPasswordHasher(Argon2Params{Memory: 8 * 1024, Iterations: 2, Parallelism: 1})
+	needsRehash, err = stronger.Verify(password, hashedPassword)
+	require.NoError(t, err)
+	require.True(t, needsRehash)
+
+	_, err = hasher.Verify(password, "plaintext")
+	require.EqualError(t, err, ErrUnknownHashFormat.Error())
+}
+
+func TestPasswordPolicy(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	content := "# comment\npassword123\n" + sha1Hex("qwertyuiop") + ":42\n"
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	policy := NewPasswordPolicy(8, 16)
+	require.NoError(t, policy.LoadBreachedPasswords(path))
+
+	require.Error(t, policy.Validate(RandomString(7)))
+	require.Error(t, policy.Validate(RandomString(17)))
+	require.Error(t, policy.Validate("password123"))
+	require.Error(t, policy.Validate("qwertyuiop"))
+	require.NoError(t, policy.Validate(RandomString(10)))
+}
@@ -1,46 +1,126 @@
 package pkg
 
 import (
-	"fmt"
 	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/i18n"
 )
 
+// MaxPrice is the highest book price the "price" tag accepts.
+const MaxPrice = 100000
+
+var slugRegexp = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// validate is shared by every request, the validator caches the parsed
+// struct tags per type.
+var validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(GetTag(field), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("price", func(fl validator.FieldLevel) bool {
+		price := fl.Field().Uint()
+		return price >= 1 && price <= MaxPrice
+	})
+	v.RegisterValidation("slug", func(fl validator.FieldLevel) bool {
+		return slugRegexp.MatchString(fl.Field().String())
+	})
+	v.RegisterValidation("locale", func(fl validator.FieldLevel) bool {
+		return i18n.IsSupported(fl.Field().String())
+	})
+	return v
+}
+
+// GetTag returns the name a field has in requests: its JSON name, or the
+// name of the route parameter, query parameter or form field it is bound to.
 func GetTag(field reflect.StructField) string {
-	fieldTag := field.Tag.Get("json")
-	if fieldTag == "" {
-		fieldTag = field.Tag.Get("uri")
+	for _, tag := range []string{"json", "params", "query", "form"} {
+		if name := field.Tag.Get(tag); name != "" {
+			return name
+		}
 	}
-	if fieldTag == "" {
-		fieldTag = field.Tag.Get("form")
+	return field.Name
+}
+
+// Validate checks req against its validate tags and reports failures as
+// apperror.ErrValidation.
+func Validate(req interface{}) error {
+	if err := validate.Struct(req); err != nil {
+		return ValidationError(err)
 	}
-	return fieldTag
+	return nil
 }
 
-func ListValidationErrors(req interface{}, validationErrors validator.ValidationErrors) []string {
-	var validation_errs = make([]string, len(validationErrors))
-	for l, validation_err := range validationErrors {
-		field, ok := reflect.TypeOf(req).Elem().FieldByName(validation_err.StructField())
-		fieldName := GetTag(field)
-		if !ok {
-			panic("Field not found")
-		}
-		validation_errs[l] = MsgForTag(validation_err, fieldName)
+// ValidationError converts a validator error into apperror.ErrValidation
+// listing every invalid field. Messages are in English, the error handler
+// translates them with the key and params kept in the field errors.
+func ValidationError(err error) error {
+	validationErrors, ok := err.(validator.ValidationErrors)
+	if !ok {
+		return apperror.ErrValidation.Wrap(err)
+	}
+
+	fields := make([]apperror.FieldError, len(validationErrors))
+	for i, validation_err := range validationErrors {
+		key, params := MsgForTag(validation_err)
+		message, _ := i18n.T(i18n.DefaultLocale, key, params...)
+		fields[i] = apperror.FieldError{Field: validation_err.Field(), Message: message, Key: key, Params: params}
+	}
+	return apperror.ErrValidation.WithFields(fields)
+}
+
+// MsgForTag returns the catalog key and params describing a validation
+// failure. Sizes have separate keys for strings and collections, so that
+// they can say characters and items.
+func MsgForTag(validation_err validator.FieldError) (string, []string) {
+	field, tag, param := validation_err.Field(), validation_err.Tag(), validation_err.Param()
+	switch tag {
+	case "required", "required_if", "required_unless", "required_with", "required_with_all", "required_without", "required_without_all":
+		tag = "required"
+	case "url", "http_url", "uri":
+		tag = "url"
+	case "uuid", "uuid3", "uuid4", "uuid5":
+		tag = "uuid"
+	case "isbn", "isbn10", "isbn13":
+		tag = "isbn"
+	case "number":
+		tag = "numeric"
+	case "price":
+		param = strconv.Itoa(MaxPrice)
+	case "locale":
+		param = strings.Join(i18n.Supported(), ", ")
+	case "oneof":
+		param = strings.ReplaceAll(param, " ", ", ")
+	case "gte":
+		tag = "min" + sizeSuffix(validation_err)
+	case "lte":
+		tag = "max" + sizeSuffix(validation_err)
+	case "min", "max", "gt", "lt", "len":
+		tag += sizeSuffix(validation_err)
+	case "email", "slug", "eq", "ne", "eqfield", "nefield", "alpha", "alphanum", "numeric", "lowercase", "uppercase", "datetime":
+	default:
+		return "validation.default", []string{field, tag}
 	}
-	return validation_errs
+	return "validation." + tag, []string{field, param}
 }
 
-func MsgForTag(validation_err validator.FieldError, fieldName string) string {
-	switch validation_err.Tag() {
-	case "required":
-		return fmt.Sprintf("Field validation for '%s' failed on the '%s' tag", fieldName, validation_err.Tag())
-	case "email":
-		return fmt.Sprintf("Invalid email")
-	case "min":
-		return fmt.Sprintf("min value for '%s' field is %s", fieldName, validation_err.Param())
-	case "max":
-		return fmt.Sprintf("max value for '%s' field is %s", fieldName, validation_err.Param())
+func sizeSuffix(validation_err validator.FieldError) string {
+	switch validation_err.Kind() {
+	case reflect.String:
+		return ".string"
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return ".items"
 	}
 	return ""
 }
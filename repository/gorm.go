@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NewGormRepositories runs every query on db.
+func NewGormRepositories(db *gorm.DB) Repositories {
+	return NewGormClusterRepositories(db, func() *gorm.DB { return db })
+}
+
+// NewGormClusterRepositories runs reads marked with FromReplica on the
+// database returned by replica and everything else on db.
+func NewGormClusterRepositories(db *gorm.DB, replica func() *gorm.DB) Repositories {
+	repos := newGormRepositories(db, replica)
+	repos.UnitOfWork = &gormUnitOfWork{db, unitOfWorkAttempts}
+	return repos
+}
+
+func newGormRepositories(db *gorm.DB, replica func() *gorm.DB) Repositories {
+	return Repositories{
+		Users:      NewGormUserRepository(db, replica),
+		Books:      NewGormBookRepository(db, replica),
+		Cart:       NewGormCartRepository(db),
+		ReadList:   NewGormReadListRepository(db),
+		ApiKeys:    NewGormApiKeyRepository(db),
+		Identities: NewGormIdentityRepository(db),
+		Exports:    NewGormDataExportRepository(db),
+	}
+}
+
+type gormReader struct {
+	primary *gorm.DB
+	replica func() *gorm.DB
+}
+
+// read returns the database for a read made with ctx.
+func (r gormReader) read(ctx context.Context) *gorm.DB {
+	if fromReplica(ctx) {
+		return r.replica().WithContext(ctx)
+	}
+	return r.primary.WithContext(ctx)
+}
+
+// translateError maps GORM errors to the repository errors.
+func translateError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ErrNotFound
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return ErrDuplicate
+	}
+	return err
+}
+
+// updateReturning applies updates to the row matched by query and loads the
+// updated row into dest. Dialects without UPDATE ... RETURNING, i.e. MySQL,
+// read the row back in the same transaction instead. It returns ErrNotFound
+// when no row matched.
+func updateReturning(db *gorm.DB, dest, updates interface{}, query interface{}, args ...interface{}) error {
+	if db.Dialector.Name() != "mysql" {
+		result := db.Model(dest).Clauses(clause.Returning{}).Where(query, args...).Updates(updates)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(dest).Where(query, args...).Updates(updates)
+		if result.Error != nil {
+			return translateError(result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrNotFound
+		}
+		return translateError(tx.Where(query, args...).First(dest).Error)
+	})
+}
+
+func omitPassword(tx *gorm.DB) *gorm.DB {
+	return tx.Omit("users.password")
+}
+
+// countByBook counts the rows of model per book, for the given books.
+func countByBook(db *gorm.DB, model interface{}, bookIDs []uint) (map[uint]int, error) {
+	var rows []struct {
+		BookID uint
+		Count  int
+	}
+	err := db.Model(model).Select("book_id, COUNT(*) AS count").
+		Where("book_id IN ?", bookIDs).Group("book_id").Scan(&rows).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	counts := make(map[uint]int, len(rows))
+	for _, row := range rows {
+		counts[row.BookID] = row.Count
+	}
+	return counts, nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+type gormApiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewGormApiKeyRepository(db *gorm.DB) ApiKeyRepository {
+	return &gormApiKeyRepository{db}
+}
+
+func (r *gormApiKeyRepository) Create(ctx context.Context, key *models.ApiKey) error {
+	return translateError(r.db.WithContext(ctx).Create(key).Error)
+}
+
+func (r *gormApiKeyRepository) GetActiveByPrefix(ctx context.Context, prefix string) (*models.ApiKey, error) {
+	var key models.ApiKey
+	err := r.db.WithContext(ctx).Preload("User").Where("revoked_at IS NULL").First(&key, &models.ApiKey{Prefix: prefix}).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &key, nil
+}
+
+func (r *gormApiKeyRepository) ListActive(ctx context.Context, userID uint) ([]models.ApiKey, error) {
+	var keys []models.ApiKey
+	err := r.db.WithContext(ctx).Where("revoked_at IS NULL").Order("created_at DESC").Find(&keys, &models.ApiKey{UserID: userID}).Error
+	return keys, translateError(err)
+}
+
+func (r *gormApiKeyRepository) Revoke(ctx context.Context, userID, id uint) error {
+	result := r.db.WithContext(ctx).Model(&models.ApiKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *gormApiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	return translateError(r.db.WithContext(ctx).Model(&models.ApiKey{ID: id}).UpdateColumn("last_used_at", at).Error)
+}
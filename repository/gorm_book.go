@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormBookRepository struct {
+	db     *gorm.DB
+	reader gormReader
+}
+
+// NewGormBookRepository runs reads marked with FromReplica on replica.
+func NewGormBookRepository(db *gorm.DB, replica func() *gorm.DB) BookRepository {
+	return &gormBookRepository{db, gormReader{db, replica}}
+}
+
+func (r *gormBookRepository) Create(ctx context.Context, book *models.Book) error {
+	return translateError(r.db.WithContext(ctx).Create(book).Error)
+}
+
+func (r *gormBookRepository) GetByID(ctx context.Context, id uint) (*models.Book, error) {
+	var book models.Book
+	err := r.reader.read(ctx).Preload("Author", omitPassword).First(&book, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &book, nil
+}
+
+func (r *gormBookRepository) List(ctx context.Context, filter BookFilter) ([]models.Book, error) {
+	var books []models.Book
+	err := r.reader.read(ctx).Preload("Author", omitPassword).
+		Where(&models.Book{AuthorID: filter.AuthorID, Title: filter.Title}).Order(clause.OrderByColumn{
+		Column: clause.Column{Name: "title"},
+		Desc:   filter.OrderDesc,
+	}).Limit(filter.Limit).Offset(filter.Offset).Find(&books).Error
+	return books, translateError(err)
+}
+
+func (r *gormBookRepository) ListByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]models.Book, error) {
+	var books []models.Book
+	err := r.reader.read(ctx).Where(&models.Book{AuthorID: authorID}).Order(clause.OrderByColumn{
+		Column: clause.Column{Name: "title"},
+	}).Limit(limit).Offset(offset).Find(&books).Error
+	return books, translateError(err)
+}
+
+func (r *gormBookRepository) Update(ctx context.Context, authorID uint, book *models.Book) (*models.Book, error) {
+	var res models.Book
+	err := updateReturning(r.db.WithContext(ctx), &res, book, &models.Book{ID: book.ID, AuthorID: authorID})
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+func (r *gormBookRepository) Delete(ctx context.Context, authorID, id uint) error {
+	result := r.db.WithContext(ctx).Delete(&models.Book{}, &models.Book{ID: id, AuthorID: authorID})
+	if result.Error != nil {
+		return translateError(result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
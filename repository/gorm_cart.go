@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+type gormCartRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCartRepository(db *gorm.DB) CartRepository {
+	return &gormCartRepository{db}
+}
+
+func (r *gormCartRepository) Add(ctx context.Context, item *models.CartItem) error {
+	return translateError(r.db.WithContext(ctx).Create(item).Error)
+}
+
+func (r *gormCartRepository) Get(ctx context.Context, userID, bookID uint) (*models.CartItem, error) {
+	var item models.CartItem
+	err := r.db.WithContext(ctx).First(&item, models.CartItem{BookID: bookID, UserID: userID}).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &item, nil
+}
+
+func (r *gormCartRepository) Contains(ctx context.Context, userID uint, bookIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.CartItem{}).Where(&models.CartItem{UserID: userID}).
+		Where("book_id IN ?", bookIDs).Pluck("book_id", &ids).Error
+	return ids, translateError(err)
+}
+
+func (r *gormCartRepository) CountByBook(ctx context.Context, bookIDs []uint) (map[uint]int, error) {
+	return countByBook(r.db.WithContext(ctx), &models.CartItem{}, bookIDs)
+}
+
+func (r *gormCartRepository) List(ctx context.Context, userID uint, limit, offset int) ([]models.CartItem, error) {
+	var items []models.CartItem
+	err := r.db.WithContext(ctx).Preload("Book", func(tx *gorm.DB) *gorm.DB {
+		return tx.Preload("Author", omitPassword)
+	}).Joins("JOIN books ON books.id = cart_items.book_id AND books.deleted_at IS NULL").Order("cart_items.created_at DESC").
+		Limit(limit).Offset(offset).Find(&items, &models.CartItem{UserID: userID}).Error
+	return items, translateError(err)
+}
+
+func (r *gormCartRepository) Remove(ctx context.Context, userID, bookID uint) error {
+	return translateError(r.db.WithContext(ctx).Delete(&models.CartItem{}, &models.CartItem{BookID: bookID, UserID: userID}).Error)
+}
+
+func (r *gormCartRepository) Clear(ctx context.Context, userID uint) error {
+	return translateError(r.db.WithContext(ctx).Delete(&models.CartItem{}, &models.CartItem{UserID: userID}).Error)
+}
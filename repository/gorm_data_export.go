@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+type gormDataExportRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDataExportRepository(db *gorm.DB) DataExportRepository {
+	return &gormDataExportRepository{db}
+}
+
+func (r *gormDataExportRepository) Get(ctx context.Context, userID uint, id string) (*models.DataExport, error) {
+	var export models.DataExport
+	err := r.db.WithContext(ctx).First(&export, &models.DataExport{ID: id, UserID: userID}).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &export, nil
+}
+
+func (r *gormDataExportRepository) Create(ctx context.Context, export *models.DataExport) error {
+	return translateError(r.db.WithContext(ctx).Create(export).Error)
+}
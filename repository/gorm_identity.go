@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+type gormIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewGormIdentityRepository(db *gorm.DB) IdentityRepository {
+	return &gormIdentityRepository{db}
+}
+
+func (r *gormIdentityRepository) GetUser(ctx context.Context, provider, subject string) (*models.User, error) {
+	var identity models.UserIdentity
+	err := r.db.WithContext(ctx).Preload("User", func(tx *gorm.DB) *gorm.DB {
+		return tx.Unscoped()
+	}).First(&identity, &models.UserIdentity{Provider: provider, Subject: subject}).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &identity.User, nil
+}
+
+func (r *gormIdentityRepository) Link(ctx context.Context, identity *models.UserIdentity, user *models.User) error {
+	return translateError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if user.ID == 0 {
+			if err := tx.Create(user).Error; err != nil {
+				return err
+			}
+		}
+		identity.UserID = user.ID
+		return tx.Omit("User").Create(identity).Error
+	}))
+}
+
+func (r *gormIdentityRepository) CreateState(ctx context.Context, state *models.OidcState) error {
+	return translateError(r.db.WithContext(ctx).Create(state).Error)
+}
+
+func (r *gormIdentityRepository) TakeState(ctx context.Context, provider, state string) (*models.OidcState, error) {
+	var res models.OidcState
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.First(&res, &models.OidcState{State: state, Provider: provider}).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Delete(&res)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &res, nil
+}
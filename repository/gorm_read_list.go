@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormReadListRepository struct {
+	db *gorm.DB
+}
+
+func NewGormReadListRepository(db *gorm.DB) ReadListRepository {
+	return &gormReadListRepository{db}
+}
+
+func (r *gormReadListRepository) Add(ctx context.Context, userID, bookID uint) error {
+	return translateError(r.db.WithContext(ctx).Create(&models.UserBook{UserID: userID, BookID: bookID}).Error)
+}
+
+func (r *gormReadListRepository) Contains(ctx context.Context, userID uint, bookIDs []uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.UserBook{}).Where(&models.UserBook{UserID: userID}).
+		Where("book_id IN ?", bookIDs).Pluck("book_id", &ids).Error
+	return ids, translateError(err)
+}
+
+func (r *gormReadListRepository) CountByBook(ctx context.Context, bookIDs []uint) (map[uint]int, error) {
+	return countByBook(r.db.WithContext(ctx), &models.UserBook{}, bookIDs)
+}
+
+func (r *gormReadListRepository) List(ctx context.Context, userID uint, limit, offset int) ([]models.UserBook, error) {
+	var entries []models.UserBook
+	err := r.db.WithContext(ctx).Preload("Book.Author", omitPassword).
+		Joins("JOIN books ON books.id = user_books.book_id AND books.deleted_at IS NULL").
+		Where(&models.UserBook{UserID: userID}).Order(clause.OrderByColumn{
+		Column: clause.Column{Table: "user_books", Name: "created_at"},
+		Desc:   true,
+	}).Limit(limit).Offset(offset).Find(&entries).Error
+	return entries, translateError(err)
+}
+
+func (r *gormReadListRepository) Remove(ctx context.Context, userID, bookID uint) error {
+	return translateError(r.db.WithContext(ctx).Model(&models.User{ID: userID}).Association("ReadList").Delete(&models.Book{ID: bookID}))
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+const (
+	unitOfWorkAttempts = 3
+	unitOfWorkBackoff  = 20 * time.Millisecond
+)
+
+type gormUnitOfWork struct {
+	db       *gorm.DB
+	attempts int
+}
+
+func (u *gormUnitOfWork) Run(ctx context.Context, fn func(repos Repositories) error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = u.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			repos := newGormRepositories(tx, func() *gorm.DB { return tx })
+			// A nested unit of work becomes a savepoint. Retrying it alone
+			// would not help, the whole transaction is aborted anyway.
+			repos.UnitOfWork = &gormUnitOfWork{tx, 1}
+			return fn(repos)
+		})
+		if attempt >= u.attempts || !retryable(err) {
+			return translateError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * unitOfWorkBackoff):
+		}
+	}
+}
+
+// retryable reports whether err aborted a transaction that may succeed when
+// run again.
+func retryable(err error) bool {
+	// Postgres deadlock. Units of work run at READ COMMITTED, which never
+	// fails with a serialization failure.
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		return pgErr.SQLState() == "40P01"
+	}
+
+	// MySQL deadlock and lock wait timeout.
+	var mysqlErr *mysqldriver.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+	}
+
+	// SQLite busy and locked, including their extended codes.
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff
+		return code == 5 || code == 6
+	}
+	return false
+}
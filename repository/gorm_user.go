@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormUserRepository struct {
+	db     *gorm.DB
+	reader gormReader
+}
+
+// NewGormUserRepository runs reads marked with FromReplica on replica.
+func NewGormUserRepository(db *gorm.DB, replica func() *gorm.DB) UserRepository {
+	return &gormUserRepository{db, gormReader{db, replica}}
+}
+
+func (r *gormUserRepository) Create(ctx context.Context, user *models.User) error {
+	return translateError(r.db.WithContext(ctx).Create(user).Error)
+}
+
+func (r *gormUserRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	err := r.reader.read(ctx).First(&user, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).First(&user, models.User{Email: email}).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) GetDeletedByEmail(ctx context.Context, email string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).Unscoped().
+		Where("deleted_at IS NOT NULL AND anonymized_at IS NULL").
+		First(&user, models.User{Email: email}).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) EmailTaken(ctx context.Context, email string) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Unscoped().Model(&models.User{}).Where("email = ?", email).Count(&count).Error
+	return count > 0, translateError(err)
+}
+
+func (r *gormUserRepository) List(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	err := r.reader.read(ctx).Find(&users).Error
+	return users, translateError(err)
+}
+
+func (r *gormUserRepository) ListAuthors(ctx context.Context, filter AuthorFilter) ([]models.User, error) {
+	var authors []models.User
+	query := r.reader.read(ctx).Preload("AuthorBooks").Where(models.User{IsAuthor: true, Name: filter.Name})
+	if len(filter.IDs) > 0 {
+		query = query.Where("id IN ?", filter.IDs)
+	}
+	err := query.Order(clause.OrderByColumn{
+		Column: clause.Column{Name: "name"},
+		Desc:   filter.OrderDesc,
+	}).Limit(filter.Limit).Offset(filter.Offset).Find(&authors).Error
+	return authors, translateError(err)
+}
+
+func (r *gormUserRepository) UpdateProfile(ctx context.Context, id uint, name, locale string) (*models.User, error) {
+	var user models.User
+	err := updateReturning(r.db.WithContext(ctx), &user, models.User{Name: name, Locale: locale}, "id = ?", id)
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	return translateError(r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("Password", hashedPassword).Error)
+}
+
+func (r *gormUserRepository) SetAuthor(ctx context.Context, id uint) error {
+	return translateError(r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", id).Update("IsAuthor", true).Error)
+}
+
+func (r *gormUserRepository) Delete(ctx context.Context, id uint) error {
+	return translateError(r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.ApiKey{}).
+			Where("user_id = ? AND revoked_at IS NULL", id).
+			Update("revoked_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+		return tx.Delete(&models.User{}, id).Error
+	}))
+}
+
+func (r *gormUserRepository) Restore(ctx context.Context, id uint) error {
+	return translateError(r.db.WithContext(ctx).Unscoped().Model(&models.User{}).Where("id = ?", id).Update("deleted_at", nil).Error)
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+// memoryStore holds the data shared by the in-memory repositories, so that
+// associations can be resolved the way the GORM preloads do.
+type memoryStore struct {
+	mu sync.Mutex
+	// work serializes units of work, which restore the maps on rollback.
+	work       sync.Mutex
+	nextID     uint
+	users      map[uint]models.User
+	books      map[uint]models.Book
+	cart       map[uint]models.CartItem
+	readList   map[uint]models.UserBook
+	apiKeys    map[uint]models.ApiKey
+	identities map[uint]models.UserIdentity
+	states     map[string]models.OidcState
+	exports    map[string]models.DataExport
+}
+
+// NewMemoryRepositories returns repositories backed by process memory. They
+// follow the GORM implementations, including soft deletes and unique
+// constraints, and are meant for tests.
+func NewMemoryRepositories() Repositories {
+	s := &memoryStore{
+		users:      map[uint]models.User{},
+		books:      map[uint]models.Book{},
+		cart:       map[uint]models.CartItem{},
+		readList:   map[uint]models.UserBook{},
+		apiKeys:    map[uint]models.ApiKey{},
+		identities: map[uint]models.UserIdentity{},
+		states:     map[string]models.OidcState{},
+		exports:    map[string]models.DataExport{},
+	}
+	repos := s.repositories()
+	repos.UnitOfWork = &memoryUnitOfWork{s, repos}
+	return repos
+}
+
+func (s *memoryStore) repositories() Repositories {
+	return Repositories{
+		Users:      &memoryUserRepository{s},
+		Books:      &memoryBookRepository{s},
+		Cart:       &memoryCartRepository{s},
+		ReadList:   &memoryReadListRepository{s},
+		ApiKeys:    &memoryApiKeyRepository{s},
+		Identities: &memoryIdentityRepository{s},
+		Exports:    &memoryDataExportRepository{s},
+	}
+}
+
+func (s *memoryStore) id() uint {
+	s.nextID++
+	return s.nextID
+}
+
+// activeUser returns a user that is not soft deleted.
+func (s *memoryStore) activeUser(id uint) (models.User, bool) {
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return models.User{}, false
+	}
+	return user, true
+}
+
+func (s *memoryStore) activeBook(id uint) (models.Book, bool) {
+	book, ok := s.books[id]
+	if !ok || book.DeletedAt.Valid {
+		return models.Book{}, false
+	}
+	return book, true
+}
+
+// author returns the preloaded author of a book without the password hash.
+func (s *memoryStore) author(book models.Book) models.User {
+	author, _ := s.activeUser(book.AuthorID)
+	author.Password = ""
+	return author
+}
+
+func (s *memoryStore) softDelete() gorm.DeletedAt {
+	return gorm.DeletedAt{Time: time.Now(), Valid: true}
+}
+
+// page applies limit and offset like SQL does. A negative limit means no
+// limit.
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+	items = items[offset:]
+	if limit >= 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
+
+func sortByNewest[T any](items []T, createdAt func(T) time.Time, id func(T) uint) {
+	sort.Slice(items, func(i, j int) bool {
+		a, b := createdAt(items[i]), createdAt(items[j])
+		if a.Equal(b) {
+			return id(items[i]) > id(items[j])
+		}
+		return a.After(b)
+	})
+}
+
+func contains(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+type memoryApiKeyRepository struct {
+	s *memoryStore
+}
+
+func (r *memoryApiKeyRepository) Create(ctx context.Context, key *models.ApiKey) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, k := range r.s.apiKeys {
+		if k.Prefix == key.Prefix {
+			return ErrDuplicate
+		}
+	}
+
+	now := time.Now()
+	key.ID = r.s.id()
+	key.CreatedAt = now
+	key.UpdatedAt = now
+
+	stored := *key
+	stored.User = models.User{}
+	r.s.apiKeys[key.ID] = stored
+	return nil
+}
+
+func (r *memoryApiKeyRepository) GetActiveByPrefix(ctx context.Context, prefix string) (*models.ApiKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, key := range r.s.apiKeys {
+		if key.Prefix == prefix && key.RevokedAt == nil {
+			key.User, _ = r.s.activeUser(key.UserID)
+			return &key, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryApiKeyRepository) ListActive(ctx context.Context, userID uint) ([]models.ApiKey, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	keys := []models.ApiKey{}
+	for _, key := range r.s.apiKeys {
+		if key.UserID == userID && key.RevokedAt == nil {
+			keys = append(keys, key)
+		}
+	}
+	sortByNewest(keys, func(v models.ApiKey) time.Time { return v.CreatedAt }, func(v models.ApiKey) uint { return v.ID })
+	return keys, nil
+}
+
+func (r *memoryApiKeyRepository) Revoke(ctx context.Context, userID, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	key, ok := r.s.apiKeys[id]
+	if !ok || key.UserID != userID || key.RevokedAt != nil {
+		return ErrNotFound
+	}
+	now := time.Now()
+	key.RevokedAt = &now
+	r.s.apiKeys[id] = key
+	return nil
+}
+
+func (r *memoryApiKeyRepository) TouchLastUsed(ctx context.Context, id uint, at time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if key, ok := r.s.apiKeys[id]; ok {
+		key.LastUsedAt = &at
+		r.s.apiKeys[id] = key
+	}
+	return nil
+}
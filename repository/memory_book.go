@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+type memoryBookRepository struct {
+	s *memoryStore
+}
+
+func (r *memoryBookRepository) Create(ctx context.Context, book *models.Book) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	book.ID = r.s.id()
+	book.CreatedAt = now
+	book.UpdatedAt = now
+
+	stored := *book
+	stored.Author = models.User{}
+	stored.ReadList = nil
+	r.s.books[book.ID] = stored
+	return nil
+}
+
+func (r *memoryBookRepository) GetByID(ctx context.Context, id uint) (*models.Book, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	book, ok := r.s.activeBook(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	book.Author = r.s.author(book)
+	return &book, nil
+}
+
+func (r *memoryBookRepository) List(ctx context.Context, filter BookFilter) ([]models.Book, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	books := []models.Book{}
+	for _, book := range r.s.books {
+		if book.DeletedAt.Valid ||
+			(filter.AuthorID != 0 && book.AuthorID != filter.AuthorID) ||
+			(filter.Title != "" && book.Title != filter.Title) {
+			continue
+		}
+		book.Author = r.s.author(book)
+		books = append(books, book)
+	}
+	sortByTitle(books, filter.OrderDesc)
+	return page(books, filter.Limit, filter.Offset), nil
+}
+
+func (r *memoryBookRepository) ListByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]models.Book, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	books := []models.Book{}
+	for _, book := range r.s.books {
+		if !book.DeletedAt.Valid && book.AuthorID == authorID {
+			books = append(books, book)
+		}
+	}
+	sortByTitle(books, false)
+	return page(books, limit, offset), nil
+}
+
+func (r *memoryBookRepository) Update(ctx context.Context, authorID uint, book *models.Book) (*models.Book, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	res, ok := r.s.activeBook(book.ID)
+	if !ok || res.AuthorID != authorID {
+		return nil, ErrNotFound
+	}
+	if book.Title != "" {
+		res.Title = book.Title
+	}
+	if book.Description != "" {
+		res.Description = book.Description
+	}
+	if book.Price != 0 {
+		res.Price = book.Price
+	}
+	if book.File != "" {
+		res.File = book.File
+	}
+	res.UpdatedAt = time.Now()
+	r.s.books[res.ID] = res
+	return &res, nil
+}
+
+func (r *memoryBookRepository) Delete(ctx context.Context, authorID, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	book, ok := r.s.activeBook(id)
+	if !ok || book.AuthorID != authorID {
+		return ErrNotFound
+	}
+	book.DeletedAt = r.s.softDelete()
+	r.s.books[id] = book
+	return nil
+}
+
+func sortByTitle(books []models.Book, desc bool) {
+	sort.Slice(books, func(i, j int) bool {
+		if desc {
+			return books[i].Title > books[j].Title
+		}
+		return books[i].Title < books[j].Title
+	})
+}
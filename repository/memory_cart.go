@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+type memoryCartRepository struct {
+	s *memoryStore
+}
+
+func (r *memoryCartRepository) Add(ctx context.Context, item *models.CartItem) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, v := range r.s.cart {
+		if v.UserID == item.UserID && v.BookID == item.BookID {
+			return ErrDuplicate
+		}
+	}
+
+	now := time.Now()
+	item.ID = r.s.id()
+	item.CreatedAt = now
+	item.UpdatedAt = now
+
+	stored := *item
+	stored.User = models.User{}
+	stored.Book = models.Book{}
+	r.s.cart[item.ID] = stored
+	return nil
+}
+
+func (r *memoryCartRepository) Get(ctx context.Context, userID, bookID uint) (*models.CartItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, item := range r.s.cart {
+		if item.UserID == userID && item.BookID == bookID {
+			return &item, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryCartRepository) Contains(ctx context.Context, userID uint, bookIDs []uint) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := []uint{}
+	for _, item := range r.s.cart {
+		if item.UserID == userID && contains(bookIDs, item.BookID) {
+			ids = append(ids, item.BookID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *memoryCartRepository) CountByBook(ctx context.Context, bookIDs []uint) (map[uint]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	counts := map[uint]int{}
+	for _, item := range r.s.cart {
+		if contains(bookIDs, item.BookID) {
+			counts[item.BookID]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryCartRepository) List(ctx context.Context, userID uint, limit, offset int) ([]models.CartItem, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	items := []models.CartItem{}
+	for _, item := range r.s.cart {
+		if item.UserID != userID {
+			continue
+		}
+		book, ok := r.s.activeBook(item.BookID)
+		if !ok {
+			continue
+		}
+		book.Author = r.s.author(book)
+		item.Book = book
+		items = append(items, item)
+	}
+	sortByNewest(items, func(v models.CartItem) time.Time { return v.CreatedAt }, func(v models.CartItem) uint { return v.ID })
+	return page(items, limit, offset), nil
+}
+
+func (r *memoryCartRepository) Remove(ctx context.Context, userID, bookID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, item := range r.s.cart {
+		if item.UserID == userID && item.BookID == bookID {
+			delete(r.s.cart, id)
+		}
+	}
+	return nil
+}
+
+func (r *memoryCartRepository) Clear(ctx context.Context, userID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, item := range r.s.cart {
+		if item.UserID == userID {
+			delete(r.s.cart, id)
+		}
+	}
+	return nil
+}
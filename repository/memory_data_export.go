@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+type memoryDataExportRepository struct {
+	s *memoryStore
+}
+
+func (r *memoryDataExportRepository) Create(ctx context.Context, export *models.DataExport) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.exports[export.ID]; ok {
+		return ErrDuplicate
+	}
+	now := time.Now()
+	export.CreatedAt = now
+	export.UpdatedAt = now
+	r.s.exports[export.ID] = *export
+	return nil
+}
+
+func (r *memoryDataExportRepository) Get(ctx context.Context, userID uint, id string) (*models.DataExport, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	export, ok := r.s.exports[id]
+	if !ok || export.UserID != userID {
+		return nil, ErrNotFound
+	}
+	return &export, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+type memoryIdentityRepository struct {
+	s *memoryStore
+}
+
+func (r *memoryIdentityRepository) GetUser(ctx context.Context, provider, subject string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, identity := range r.s.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			user := r.s.users[identity.UserID]
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryIdentityRepository) Link(ctx context.Context, identity *models.UserIdentity, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, v := range r.s.identities {
+		if v.Provider == identity.Provider && v.Subject == identity.Subject {
+			return ErrDuplicate
+		}
+	}
+	if user.ID == 0 {
+		if err := r.s.createUser(user); err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	identity.ID = r.s.id()
+	identity.UserID = user.ID
+	identity.CreatedAt = now
+	identity.UpdatedAt = now
+
+	stored := *identity
+	stored.User = models.User{}
+	r.s.identities[identity.ID] = stored
+	return nil
+}
+
+func (r *memoryIdentityRepository) CreateState(ctx context.Context, state *models.OidcState) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.states[state.State]; ok {
+		return ErrDuplicate
+	}
+	state.CreatedAt = time.Now()
+	r.s.states[state.State] = *state
+	return nil
+}
+
+func (r *memoryIdentityRepository) TakeState(ctx context.Context, provider, state string) (*models.OidcState, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	res, ok := r.s.states[state]
+	if !ok || res.Provider != provider {
+		return nil, ErrNotFound
+	}
+	delete(r.s.states, state)
+	return &res, nil
+}
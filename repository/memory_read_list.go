@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+type memoryReadListRepository struct {
+	s *memoryStore
+}
+
+func (r *memoryReadListRepository) Add(ctx context.Context, userID, bookID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, entry := range r.s.readList {
+		if entry.UserID == userID && entry.BookID == bookID {
+			return ErrDuplicate
+		}
+	}
+
+	id := r.s.id()
+	r.s.readList[id] = models.UserBook{ID: id, CreatedAt: time.Now(), UserID: userID, BookID: bookID}
+	return nil
+}
+
+func (r *memoryReadListRepository) Contains(ctx context.Context, userID uint, bookIDs []uint) ([]uint, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	ids := []uint{}
+	for _, entry := range r.s.readList {
+		if entry.UserID == userID && contains(bookIDs, entry.BookID) {
+			ids = append(ids, entry.BookID)
+		}
+	}
+	return ids, nil
+}
+
+func (r *memoryReadListRepository) CountByBook(ctx context.Context, bookIDs []uint) (map[uint]int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	counts := map[uint]int{}
+	for _, entry := range r.s.readList {
+		if contains(bookIDs, entry.BookID) {
+			counts[entry.BookID]++
+		}
+	}
+	return counts, nil
+}
+
+func (r *memoryReadListRepository) List(ctx context.Context, userID uint, limit, offset int) ([]models.UserBook, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	entries := []models.UserBook{}
+	for _, entry := range r.s.readList {
+		if entry.UserID != userID {
+			continue
+		}
+		book, ok := r.s.activeBook(entry.BookID)
+		if !ok {
+			continue
+		}
+		book.Author = r.s.author(book)
+		entry.Book = book
+		entries = append(entries, entry)
+	}
+	sortByNewest(entries, func(v models.UserBook) time.Time { return v.CreatedAt }, func(v models.UserBook) uint { return v.ID })
+	return page(entries, limit, offset), nil
+}
+
+func (r *memoryReadListRepository) Remove(ctx context.Context, userID, bookID uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, entry := range r.s.readList {
+		if entry.UserID == userID && entry.BookID == bookID {
+			delete(r.s.readList, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+func TestMemoryUsers(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMemoryRepositories()
+
+	user := &models.User{Name: "Ann", Email: "ann@example.com"}
+	require.NoError(t, repos.Users.Create(ctx, user))
+	require.NotZero(t, user.ID)
+
+	err := repos.Users.Create(ctx, &models.User{Name: "Ann", Email: "ann@example.com"})
+	require.ErrorIs(t, err, ErrDuplicate)
+
+	require.NoError(t, repos.Users.Delete(ctx, user.ID))
+	_, err = repos.Users.GetByID(ctx, user.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+
+	taken, err := repos.Users.EmailTaken(ctx, user.Email)
+	require.NoError(t, err)
+	require.True(t, taken)
+
+	deleted, err := repos.Users.GetDeletedByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	require.NoError(t, repos.Users.Restore(ctx, deleted.ID))
+
+	restored, err := repos.Users.GetByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, restored.ID)
+}
+
+func TestMemoryBooksAndLists(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMemoryRepositories()
+
+	author := &models.User{Name: "Bob", Email: "bob@example.com", Password: "secret", IsAuthor: true}
+	require.NoError(t, repos.Users.Create(ctx, author))
+
+	for _, title := range []string{"b", "a", "c"} {
+		require.NoError(t, repos.Books.Create(ctx, &models.Book{Title: title, AuthorID: author.ID}))
+	}
+
+	books, err := repos.Books.List(ctx, BookFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, books, 2)
+	require.Equal(t, "a", books[0].Title)
+	require.Equal(t, "Bob", books[0].Author.Name)
+	require.Empty(t, books[0].Author.Password)
+
+	_, err = repos.Books.Update(ctx, author.ID+1, &models.Book{ID: books[0].ID, Title: "z"})
+	require.ErrorIs(t, err, ErrNotFound)
+
+	require.NoError(t, repos.ReadList.Add(ctx, author.ID, books[0].ID))
+	require.ErrorIs(t, repos.ReadList.Add(ctx, author.ID, books[0].ID), ErrDuplicate)
+	require.NoError(t, repos.Cart.Add(ctx, &models.CartItem{UserID: author.ID, BookID: books[0].ID}))
+
+	require.NoError(t, repos.Books.Delete(ctx, author.ID, books[0].ID))
+
+	entries, err := repos.ReadList.List(ctx, author.ID, 20, 0)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+
+	items, err := repos.Cart.List(ctx, author.ID, 20, 0)
+	require.NoError(t, err)
+	require.Empty(t, items)
+}
+
+func TestMemoryUnitOfWork(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMemoryRepositories()
+
+	err := repos.UnitOfWork.Run(ctx, func(repos Repositories) error {
+		if err := repos.Users.Create(ctx, &models.User{Name: "Ann", Email: "ann@example.com"}); err != nil {
+			return err
+		}
+		return repos.Users.Create(ctx, &models.User{Name: "Ann", Email: "ann@example.com"})
+	})
+	require.ErrorIs(t, err, ErrDuplicate)
+
+	_, err = repos.Users.GetByEmail(ctx, "ann@example.com")
+	require.ErrorIs(t, err, ErrNotFound)
+
+	err = repos.UnitOfWork.Run(ctx, func(repos Repositories) error {
+		return repos.Users.Create(ctx, &models.User{Name: "Ann", Email: "ann@example.com"})
+	})
+	require.NoError(t, err)
+
+	_, err = repos.Users.GetByEmail(ctx, "ann@example.com")
+	require.NoError(t, err)
+}
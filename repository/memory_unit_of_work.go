@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+type memoryUnitOfWork struct {
+	s     *memoryStore
+	repos Repositories
+}
+
+func (u *memoryUnitOfWork) Run(ctx context.Context, fn func(repos Repositories) error) error {
+	u.s.work.Lock()
+	defer u.s.work.Unlock()
+
+	snapshot := u.s.snapshot()
+	repos := u.repos
+	repos.UnitOfWork = nestedMemoryUnitOfWork{repos}
+	if err := fn(repos); err != nil {
+		u.s.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+// nestedMemoryUnitOfWork runs inside a unit of work, which already holds the
+// lock and rolls back everything on error.
+type nestedMemoryUnitOfWork struct {
+	repos Repositories
+}
+
+func (u nestedMemoryUnitOfWork) Run(ctx context.Context, fn func(repos Repositories) error) error {
+	return fn(u.repos)
+}
+
+type memorySnapshot struct {
+	nextID     uint
+	users      map[uint]models.User
+	books      map[uint]models.Book
+	cart       map[uint]models.CartItem
+	readList   map[uint]models.UserBook
+	apiKeys    map[uint]models.ApiKey
+	identities map[uint]models.UserIdentity
+	states     map[string]models.OidcState
+	exports    map[string]models.DataExport
+}
+
+func (s *memoryStore) snapshot() memorySnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return memorySnapshot{
+		nextID:     s.nextID,
+		users:      cloneMap(s.users),
+		books:      cloneMap(s.books),
+		cart:       cloneMap(s.cart),
+		readList:   cloneMap(s.readList),
+		apiKeys:    cloneMap(s.apiKeys),
+		identities: cloneMap(s.identities),
+		states:     cloneMap(s.states),
+		exports:    cloneMap(s.exports),
+	}
+}
+
+func (s *memoryStore) restore(snapshot memorySnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.nextID = snapshot.nextID
+	s.users = snapshot.users
+	s.books = snapshot.books
+	s.cart = snapshot.cart
+	s.readList = snapshot.readList
+	s.apiKeys = snapshot.apiKeys
+	s.identities = snapshot.identities
+	s.states = snapshot.states
+	s.exports = snapshot.exports
+}
+
+func cloneMap[K comparable, V any](m map[K]V) map[K]V {
+	res := make(map[K]V, len(m))
+	for k, v := range m {
+		res[k] = v
+	}
+	return res
+}
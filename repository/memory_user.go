@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+type memoryUserRepository struct {
+	s *memoryStore
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.createUser(user)
+}
+
+func (s *memoryStore) createUser(user *models.User) error {
+	for _, u := range s.users {
+		if u.Email == user.Email {
+			return ErrDuplicate
+		}
+	}
+
+	now := time.Now()
+	user.ID = s.id()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	stored := *user
+	stored.ReadList = nil
+	stored.AuthorBooks = nil
+	s.users[user.ID] = stored
+	return nil
+}
+
+func (r *memoryUserRepository) GetByID(ctx context.Context, id uint) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.activeUser(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email && !user.DeletedAt.Valid {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) GetDeletedByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email && user.DeletedAt.Valid && user.AnonymizedAt == nil {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) EmailTaken(ctx context.Context, email string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryUserRepository) List(ctx context.Context) ([]models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	users := []models.User{}
+	for _, user := range r.s.users {
+		if !user.DeletedAt.Valid {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].ID < users[j].ID
+	})
+	return users, nil
+}
+
+func (r *memoryUserRepository) ListAuthors(ctx context.Context, filter AuthorFilter) ([]models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	authors := []models.User{}
+	for _, user := range r.s.users {
+		if user.DeletedAt.Valid || !user.IsAuthor || (filter.Name != "" && user.Name != filter.Name) {
+			continue
+		}
+		if len(filter.IDs) > 0 && !contains(filter.IDs, user.ID) {
+			continue
+		}
+		user.AuthorBooks = []models.Book{}
+		for _, book := range r.s.books {
+			if book.AuthorID == user.ID && !book.DeletedAt.Valid {
+				user.AuthorBooks = append(user.AuthorBooks, book)
+			}
+		}
+		authors = append(authors, user)
+	}
+	sort.Slice(authors, func(i, j int) bool {
+		if filter.OrderDesc {
+			return authors[i].Name > authors[j].Name
+		}
+		return authors[i].Name < authors[j].Name
+	})
+	return page(authors, filter.Limit, filter.Offset), nil
+}
+
+func (r *memoryUserRepository) UpdateProfile(ctx context.Context, id uint, name, locale string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.activeUser(id)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if name != "" {
+		user.Name = name
+	}
+	if locale != "" {
+		user.Locale = locale
+	}
+	user.UpdatedAt = time.Now()
+	r.s.users[id] = user
+	return &user, nil
+}
+
+func (r *memoryUserRepository) UpdatePassword(ctx context.Context, id uint, hashedPassword string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user, ok := r.s.activeUser(id); ok {
+		user.Password = hashedPassword
+		user.UpdatedAt = time.Now()
+		r.s.users[id] = user
+	}
+	return nil
+}
+
+func (r *memoryUserRepository) SetAuthor(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user, ok := r.s.activeUser(id); ok {
+		user.IsAuthor = true
+		user.UpdatedAt = time.Now()
+		r.s.users[id] = user
+	}
+	return nil
+}
+
+func (r *memoryUserRepository) Delete(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for keyID, key := range r.s.apiKeys {
+		if key.UserID == id && key.RevokedAt == nil {
+			key.RevokedAt = &now
+			r.s.apiKeys[keyID] = key
+		}
+	}
+	if user, ok := r.s.activeUser(id); ok {
+		user.DeletedAt = r.s.softDelete()
+		r.s.users[id] = user
+	}
+	return nil
+}
+
+func (r *memoryUserRepository) Restore(ctx context.Context, id uint) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user, ok := r.s.users[id]; ok {
+		user.DeletedAt = gorm.DeletedAt{}
+		r.s.users[id] = user
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package repository isolates data access behind interfaces so handlers do
+// not depend on GORM. Every interface has a GORM implementation used in
+// production and an in-memory implementation for tests and demos.
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/models"
+)
+
+var (
+	ErrNotFound  = errors.New("record not found")
+	ErrDuplicate = errors.New("record already exists")
+)
+
+type BookFilter struct {
+	Limit     int
+	Offset    int
+	Title     string
+	AuthorID  uint
+	OrderDesc bool
+}
+
+type AuthorFilter struct {
+	Limit     int
+	Offset    int
+	Name      string
+	OrderDesc bool
+	// IDs limits the authors to the given ones when it isn't empty.
+	IDs []uint
+}
+
+type UserRepository interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id uint) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	// GetDeletedByEmail returns a soft deleted user that was not anonymized yet.
+	GetDeletedByEmail(ctx context.Context, email string) (*models.User, error)
+	// EmailTaken also counts soft deleted users, which keep their email until
+	// they are anonymized.
+	EmailTaken(ctx context.Context, email string) (bool, error)
+	List(ctx context.Context) ([]models.User, error)
+	ListAuthors(ctx context.Context, filter AuthorFilter) ([]models.User, error)
+	// UpdateProfile changes the name and preferred locale, empty values are
+	// left unchanged.
+	UpdateProfile(ctx context.Context, id uint, name, locale string) (*models.User, error)
+	UpdatePassword(ctx context.Context, id uint, hashedPassword string) error
+	SetAuthor(ctx context.Context, id uint) error
+	// Delete soft deletes the user and revokes their API keys.
+	Delete(ctx context.Context, id uint) error
+	Restore(ctx context.Context, id uint) error
+}
+
+type BookRepository interface {
+	Create(ctx context.Context, book *models.Book) error
+	// GetByID returns the book with its author preloaded.
+	GetByID(ctx context.Context, id uint) (*models.Book, error)
+	List(ctx context.Context, filter BookFilter) ([]models.Book, error)
+	ListByAuthor(ctx context.Context, authorID uint, limit, offset int) ([]models.Book, error)
+	// Update changes the non-zero fields of book if it belongs to authorID.
+	Update(ctx context.Context, authorID uint, book *models.Book) (*models.Book, error)
+	Delete(ctx context.Context, authorID, id uint) error
+}
+
+type CartRepository interface {
+	Add(ctx context.Context, item *models.CartItem) error
+	Get(ctx context.Context, userID, bookID uint) (*models.CartItem, error)
+	// Contains returns the ones of bookIDs that are in the user's cart.
+	Contains(ctx context.Context, userID uint, bookIDs []uint) ([]uint, error)
+	// CountByBook returns how many carts hold each of bookIDs, books in no
+	// cart are left out.
+	CountByBook(ctx context.Context, bookIDs []uint) (map[uint]int, error)
+	// List returns cart items with book and author preloaded, skipping deleted
+	// books, newest first.
+	List(ctx context.Context, userID uint, limit, offset int) ([]models.CartItem, error)
+	Remove(ctx context.Context, userID, bookID uint) error
+	Clear(ctx context.Context, userID uint) error
+}
+
+type ReadListRepository interface {
+	Add(ctx context.Context, userID, bookID uint) error
+	// Contains returns the ones of bookIDs that are in the user's read list.
+	Contains(ctx context.Context, userID uint, bookIDs []uint) ([]uint, error)
+	// CountByBook returns how many read lists hold each of bookIDs, books in
+	// no read list are left out.
+	CountByBook(ctx context.Context, bookIDs []uint) (map[uint]int, error)
+	// List returns entries with book and author preloaded, skipping deleted
+	// books, newest first.
+	List(ctx context.Context, userID uint, limit, offset int) ([]models.UserBook, error)
+	Remove(ctx context.Context, userID, bookID uint) error
+}
+
+type ApiKeyRepository interface {
+	Create(ctx context.Context, key *models.ApiKey) error
+	// GetActiveByPrefix returns a non revoked key with its user preloaded.
+	GetActiveByPrefix(ctx context.Context, prefix string) (*models.ApiKey, error)
+	ListActive(ctx context.Context, userID uint) ([]models.ApiKey, error)
+	Revoke(ctx context.Context, userID, id uint) error
+	TouchLastUsed(ctx context.Context, id uint, at time.Time) error
+}
+
+type IdentityRepository interface {
+	// GetUser returns the user linked to the identity. A soft deleted user is
+	// returned with DeletedAt set.
+	GetUser(ctx context.Context, provider, subject string) (*models.User, error)
+	// Link creates the identity for user in one transaction with the user
+	// itself when it has no ID yet.
+	Link(ctx context.Context, identity *models.UserIdentity, user *models.User) error
+	CreateState(ctx context.Context, state *models.OidcState) error
+	// TakeState returns and deletes a login state, so it can be used once.
+	TakeState(ctx context.Context, provider, state string) (*models.OidcState, error)
+}
+
+type DataExportRepository interface {
+	Create(ctx context.Context, export *models.DataExport) error
+	Get(ctx context.Context, userID uint, id string) (*models.DataExport, error)
+}
+
+// UnitOfWork runs multi-step operations atomically.
+type UnitOfWork interface {
+	// Run calls fn with repositories that share one transaction. It commits
+	// when fn returns nil and rolls back otherwise. When the database aborts
+	// the transaction on a serialization failure or deadlock, fn is run again
+	// from the start, so it must not have side effects outside repos.
+	Run(ctx context.Context, fn func(repos Repositories) error) error
+}
+
+type replicaKey struct{}
+
+// FromReplica marks the reads made with ctx as tolerating replication lag, so
+// they may be served by a read replica. Unmarked reads use the primary.
+func FromReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, replicaKey{}, true)
+}
+
+func fromReplica(ctx context.Context) bool {
+	replica, _ := ctx.Value(replicaKey{}).(bool)
+	return replica
+}
+
+// Repositories groups the repositories the routers depend on.
+type Repositories struct {
+	Users      UserRepository
+	Books      BookRepository
+	Cart       CartRepository
+	ReadList   ReadListRepository
+	ApiKeys    ApiKeyRepository
+	Identities IdentityRepository
+	Exports    DataExportRepository
+	UnitOfWork UnitOfWork
+}
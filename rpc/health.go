@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/api/health"
+	"github.com/zura-t/bookstore_fiber/logger"
+	usersv1 "github.com/zura-t/bookstore_fiber/proto/users/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// healthServer answers the standard health checks with the readiness checks
+// of the HTTP server, for the whole server ("") and the users service. Watch
+// isn't implemented, clients poll Check.
+type healthServer struct {
+	grpc_health_v1.UnimplementedHealthServer
+	checks []health.Check
+}
+
+func (s *healthServer) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	switch req.GetService() {
+	case "", usersv1.UsersService_ServiceDesc.ServiceName:
+	default:
+		return nil, status.Errorf(codes.NotFound, "unknown service %q", req.GetService())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, health.CheckTimeout)
+	defer cancel()
+
+	res := &grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVING}
+	for _, check := range s.checks {
+		if err := check.Run(ctx); err != nil {
+			logger.FromContext(ctx, logrus.StandardLogger()).WithField("check", check.Name).Error(err)
+			res.Status = grpc_health_v1.HealthCheckResponse_NOT_SERVING
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,137 @@
+// Package rpc serves the gRPC API other internal services use to look up
+// users, verify the tokens clients send them and check entitlements. It
+// runs alongside the HTTP server on USERS_SERVICE_ADDRESS and calls the same
+// services.
+package rpc
+
+//go:generate protoc -I ../proto --go_out=../proto --go_opt=paths=source_relative --go-grpc_out=../proto --go-grpc_opt=paths=source_relative users/v1/users.proto
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/api/health"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/logger"
+	usersv1 "github.com/zura-t/bookstore_fiber/proto/users/v1"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo details, whose reason is the
+// code of the application error, as in the problems of the HTTP API.
+const ErrorDomain = "bookstore"
+
+var codesByKind = map[apperror.Kind]codes.Code{
+	apperror.Internal:        codes.Internal,
+	apperror.Invalid:         codes.InvalidArgument,
+	apperror.Unauthorized:    codes.Unauthenticated,
+	apperror.Forbidden:       codes.PermissionDenied,
+	apperror.NotFound:        codes.NotFound,
+	apperror.Conflict:        codes.AlreadyExists,
+	apperror.Gone:            codes.NotFound,
+	apperror.TooManyRequests: codes.ResourceExhausted,
+	apperror.Unavailable:     codes.Unavailable,
+	apperror.BadGateway:      codes.Unavailable,
+}
+
+// NewServer registers the users and health services. A non empty
+// serviceToken must be sent by clients as a Bearer token in the
+// authorization metadata, except for health checks. The configuration only
+// allows an empty token on loopback addresses.
+func NewServer(log *logrus.Logger, serviceToken string, services service.Services, maker *token.JwtMaker, checks []health.Check) *grpc.Server {
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		logRequests(log),
+		authenticate(serviceToken),
+	))
+	usersv1.RegisterUsersServiceServer(server, &usersServer{services: services, maker: maker})
+	grpc_health_v1.RegisterHealthServer(server, &healthServer{checks: checks})
+	return server
+}
+
+// logRequests logs one line per call, like the HTTP logger middleware, and
+// turns errors into statuses. Application errors keep their code as the
+// reason of an ErrorInfo detail, other errors are internal and only logged.
+func logRequests(log *logrus.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (res interface{}, err error) {
+		start := time.Now()
+		entry := log.WithField("method", info.FullMethod)
+		ctx = logger.NewContext(ctx, entry)
+
+		defer func() {
+			if p := recover(); p != nil {
+				entry.WithField("panic", p).Error("panic in handler")
+				res, err = nil, apperror.ErrInternal
+			}
+
+			st := toStatus(entry, err)
+			err = st.Err()
+			entry := entry.WithFields(logrus.Fields{
+				"code":       st.Code().String(),
+				"latency_ms": float64(time.Since(start).Microseconds()) / 1000,
+			})
+			switch st.Code() {
+			case codes.OK:
+				entry.Info("call")
+			case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss:
+				entry.Error("call")
+			default:
+				entry.Warn("call")
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+func toStatus(log *logrus.Entry, err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+	if st, ok := status.FromError(err); ok {
+		return st
+	}
+
+	var appErr *apperror.Error
+	if !errors.As(err, &appErr) {
+		appErr = apperror.ErrInternal
+	}
+	if appErr.Kind == apperror.Internal {
+		log.WithField("error_code", appErr.Code).Error(err)
+	}
+
+	st := status.New(codesByKind[appErr.Kind], appErr.Message)
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: appErr.Code, Domain: ErrorDomain}); err == nil {
+		return detailed
+	}
+	return st
+}
+
+// authenticate skips the health service, so that probes don't need the
+// token.
+func authenticate(serviceToken string) grpc.UnaryServerInterceptor {
+	expected := []byte("Bearer " + serviceToken)
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if serviceToken == "" {
+			return handler(ctx, req)
+		}
+		if _, ok := info.Server.(grpc_health_v1.HealthServer); ok {
+			return handler(ctx, req)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) != 1 || subtle.ConstantTimeCompare([]byte(values[0]), expected) != 1 {
+			return nil, apperror.ErrUnauthenticated
+		}
+		return handler(ctx, req)
+	}
+}
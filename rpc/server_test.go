@@ -0,0 +1,166 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/api/health"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	usersv1 "github.com/zura-t/bookstore_fiber/proto/users/v1"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/rpc"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const serviceToken = "internal-token"
+
+// dial starts the server on an in-memory listener.
+func dial(t *testing.T, services service.Services, maker *token.JwtMaker, checks []health.Check) *grpc.ClientConn {
+	t.Helper()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	server := rpc.NewServer(log, serviceToken, services, maker, checks)
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func requireCode(t *testing.T, err error, code codes.Code, reason string) {
+	t.Helper()
+
+	st, ok := status.FromError(err)
+	require.True(t, ok, err)
+	require.Equal(t, code, st.Code(), st.Message())
+	if reason != "" {
+		require.Len(t, st.Details(), 1)
+		require.Equal(t, reason, st.Details()[0].(*errdetails.ErrorInfo).Reason)
+	}
+}
+
+func TestUsersService(t *testing.T) {
+	hasher := pkg.NewPasswordHasher(pkg.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1})
+	services := service.New(logrus.New(), config.Config{}, repository.NewMemoryRepositories(), hasher, pkg.NewPasswordPolicy(8, 64), nil)
+	maker, err := token.NewJwtMaker(logrus.New(), pkg.RandomString(32))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	author, err := services.Users.Register(ctx, "Ann", "ann@example.com", "long enough password")
+	require.NoError(t, err)
+	reader, err := services.Users.Register(ctx, "Bob", "bob@example.com", "long enough password")
+	require.NoError(t, err)
+	book, err := services.Books.Publish(ctx, author.Id, entity.Book{Title: "Dune", Price: 10})
+	require.NoError(t, err)
+	accessToken, _, err := maker.CreateToken(reader.Id, reader.Email, time.Minute)
+	require.NoError(t, err)
+	_, apiKey, err := services.ApiKeys.Create(ctx, author.Id, "ci", []string{token.ScopeCatalogRead}, 0)
+	require.NoError(t, err)
+
+	client := usersv1.NewUsersServiceClient(dial(t, services, maker, nil))
+	authCtx := metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+serviceToken)
+
+	t.Run("service token", func(t *testing.T) {
+		_, err := client.GetUser(ctx, &usersv1.GetUserRequest{Id: uint64(author.Id)})
+		requireCode(t, err, codes.Unauthenticated, "unauthenticated")
+	})
+
+	t.Run("get user", func(t *testing.T) {
+		res, err := client.GetUser(authCtx, &usersv1.GetUserRequest{Id: uint64(author.Id)})
+		require.NoError(t, err)
+		require.Equal(t, "ann@example.com", res.GetUser().GetEmail())
+		require.Equal(t, "Ann", res.GetUser().GetName())
+
+		_, err = client.GetUser(authCtx, &usersv1.GetUserRequest{Id: 9999})
+		requireCode(t, err, codes.NotFound, "user_not_found")
+		_, err = client.GetUser(authCtx, &usersv1.GetUserRequest{})
+		requireCode(t, err, codes.InvalidArgument, "invalid_params")
+	})
+
+	t.Run("verify token", func(t *testing.T) {
+		res, err := client.VerifyToken(authCtx, &usersv1.VerifyTokenRequest{Token: accessToken})
+		require.NoError(t, err)
+		require.Equal(t, uint64(reader.Id), res.GetUserId())
+		require.Equal(t, usersv1.TokenKind_TOKEN_KIND_ACCESS_TOKEN, res.GetKind())
+		require.NotNil(t, res.GetExpiresAt())
+
+		res, err = client.VerifyToken(authCtx, &usersv1.VerifyTokenRequest{Token: apiKey})
+		require.NoError(t, err)
+		require.Equal(t, uint64(author.Id), res.GetUserId())
+		require.Equal(t, usersv1.TokenKind_TOKEN_KIND_API_KEY, res.GetKind())
+		require.Equal(t, []string{token.ScopeCatalogRead}, res.GetScopes())
+		require.NotNil(t, res.GetExpiresAt())
+
+		_, err = client.VerifyToken(authCtx, &usersv1.VerifyTokenRequest{Token: accessToken + "x"})
+		requireCode(t, err, codes.Unauthenticated, "unauthenticated")
+	})
+
+	t.Run("verify token of deleted user", func(t *testing.T) {
+		deleted, err := services.Users.Register(ctx, "Eve", "eve@example.com", "long enough password")
+		require.NoError(t, err)
+		deletedToken, _, err := maker.CreateToken(deleted.Id, deleted.Email, time.Minute)
+		require.NoError(t, err)
+		_, err = services.Users.Delete(ctx, deleted.Id)
+		require.NoError(t, err)
+
+		_, err = client.VerifyToken(authCtx, &usersv1.VerifyTokenRequest{Token: deletedToken})
+		requireCode(t, err, codes.Unauthenticated, "unauthenticated")
+	})
+
+	t.Run("check entitlement", func(t *testing.T) {
+		res, err := client.CheckEntitlement(authCtx, &usersv1.CheckEntitlementRequest{UserId: uint64(author.Id), BookId: uint64(book.Id)})
+		require.NoError(t, err)
+		require.True(t, res.GetEntitled())
+
+		res, err = client.CheckEntitlement(authCtx, &usersv1.CheckEntitlementRequest{UserId: uint64(reader.Id), BookId: uint64(book.Id)})
+		require.NoError(t, err)
+		require.False(t, res.GetEntitled())
+
+		_, err = client.CheckEntitlement(authCtx, &usersv1.CheckEntitlementRequest{UserId: uint64(reader.Id), BookId: 9999})
+		requireCode(t, err, codes.NotFound, "book_not_found")
+	})
+}
+
+func TestHealth(t *testing.T) {
+	var failure error
+	checks := []health.Check{{Name: "database", Run: func(ctx context.Context) error { return failure }}}
+	client := grpc_health_v1.NewHealthClient(dial(t, service.Services{}, nil, checks))
+	ctx := context.Background()
+
+	// Probes don't send the service token.
+	res, err := client.Check(ctx, &grpc_health_v1.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_SERVING, res.GetStatus())
+
+	failure = errors.New("connection refused")
+	res, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "bookstore.users.v1.UsersService"})
+	require.NoError(t, err)
+	require.Equal(t, grpc_health_v1.HealthCheckResponse_NOT_SERVING, res.GetStatus())
+
+	_, err = client.Check(ctx, &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	requireCode(t, err, codes.NotFound, "")
+}
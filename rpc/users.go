@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/logger"
+	usersv1 "github.com/zura-t/bookstore_fiber/proto/users/v1"
+	"github.com/zura-t/bookstore_fiber/service"
+	"github.com/zura-t/bookstore_fiber/token"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type usersServer struct {
+	usersv1.UnimplementedUsersServiceServer
+	services service.Services
+	maker    *token.JwtMaker
+}
+
+func (s *usersServer) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.GetUserResponse, error) {
+	id, err := parseID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.services.Users.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return &usersv1.GetUserResponse{User: convertUser(*user)}, nil
+}
+
+// VerifyToken checks tokens the way the auth middleware does, tokens of
+// deleted users are rejected and API keys are marked as used.
+func (s *usersServer) VerifyToken(ctx context.Context, req *usersv1.VerifyTokenRequest) (*usersv1.VerifyTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, apperror.ErrUnauthenticated
+	}
+
+	if !token.IsApiKey(req.GetToken()) {
+		payload, err := s.maker.VerifyToken(req.GetToken())
+		if err != nil {
+			return nil, apperror.ErrUnauthenticated.Wrap(err)
+		}
+		if err := s.services.Users.CheckActive(ctx, payload.UserId); err != nil {
+			if errors.Is(err, service.ErrAccountDeleted) {
+				return nil, apperror.ErrUnauthenticated.Wrap(err)
+			}
+			return nil, err
+		}
+		return &usersv1.VerifyTokenResponse{
+			UserId:    uint64(payload.UserId),
+			Email:     payload.Email,
+			Kind:      usersv1.TokenKind_TOKEN_KIND_ACCESS_TOKEN,
+			ExpiresAt: timestamppb.New(payload.ExpiredAt),
+		}, nil
+	}
+
+	key, err := s.services.ApiKeys.Authenticate(ctx, req.GetToken())
+	if err != nil {
+		return nil, apperror.ErrUnauthenticated.Wrap(err)
+	}
+	if err := s.services.ApiKeys.MarkUsed(ctx, key); err != nil {
+		logger.FromContext(ctx, logrus.StandardLogger()).Warn(err)
+	}
+
+	res := &usersv1.VerifyTokenResponse{
+		UserId: uint64(key.User.Id),
+		Email:  key.User.Email,
+		Kind:   usersv1.TokenKind_TOKEN_KIND_API_KEY,
+		Scopes: key.Scopes,
+	}
+	if key.ExpiresAt != nil {
+		res.ExpiresAt = timestamppb.New(*key.ExpiresAt)
+	}
+	return res, nil
+}
+
+func (s *usersServer) CheckEntitlement(ctx context.Context, req *usersv1.CheckEntitlementRequest) (*usersv1.CheckEntitlementResponse, error) {
+	userId, err := parseID(req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	bookId, err := parseID(req.GetBookId())
+	if err != nil {
+		return nil, err
+	}
+
+	owns, err := s.services.Books.Owns(ctx, userId, bookId)
+	if err != nil {
+		return nil, err
+	}
+	return &usersv1.CheckEntitlementResponse{Entitled: owns}, nil
+}
+
+func convertUser(user entity.User) *usersv1.User {
+	return &usersv1.User{
+		Id:        uint64(user.Id),
+		Email:     user.Email,
+		Name:      user.Name,
+		IsAuthor:  user.IsAuthor,
+		Locale:    user.Locale,
+		CreatedAt: timestamppb.New(user.CreatedAt),
+	}
+}
+
+// parseID rejects 0, the value of ids clients didn't set, and ids that
+// don't fit in a uint.
+func parseID(id uint64) (uint, error) {
+	if id == 0 || uint64(uint(id)) != id {
+		return 0, apperror.ErrInvalidParams
+	}
+	return uint(id), nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+const defaultApiKeyDays = 90
+
+// lastUsedResolution limits how often last_used_at is written for a key.
+const lastUsedResolution = time.Minute
+
+// apiKeyAttempts bounds the keys Create generates. The lookup prefix is only
+// 32 bits, so it may collide with the prefix of an existing key.
+const apiKeyAttempts = 3
+
+type ApiKeyService struct {
+	apiKeys repository.ApiKeyRepository
+	// generate is token.GenerateApiKey, replaced in tests.
+	generate func() (key, prefix, hash string, err error)
+}
+
+// Create issues a new key for the user and returns it with the plaintext
+// key, which is not stored and can't be shown again.
+func (s *ApiKeyService) Create(ctx context.Context, userId uint, name string, scopes []string, expiresInDays int) (*entity.ApiKey, string, error) {
+	for _, scope := range scopes {
+		if !token.ValidScope(scope) {
+			return nil, "", fmt.Errorf("%w '%s', available scopes: %s", ErrUnknownScope, scope, strings.Join(token.Scopes, ", "))
+		}
+	}
+
+	if expiresInDays == 0 {
+		expiresInDays = defaultApiKeyDays
+	}
+	expiresAt := time.Now().AddDate(0, 0, expiresInDays)
+
+	for attempt := 1; ; attempt++ {
+		key, prefix, hash, err := s.generate()
+		if err != nil {
+			return nil, "", err
+		}
+
+		apiKey := ApiKeyToModel(entity.ApiKey{
+			User:      entity.User{Id: userId},
+			Name:      name,
+			Prefix:    prefix,
+			Scopes:    scopes,
+			ExpiresAt: &expiresAt,
+		})
+		apiKey.Hash = hash
+
+		err = s.apiKeys.Create(ctx, &apiKey)
+		if errors.Is(err, repository.ErrDuplicate) && attempt < apiKeyAttempts {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+
+		res := ApiKeyFromModel(apiKey)
+		return &res, key, nil
+	}
+}
+
+func (s *ApiKeyService) List(ctx context.Context, userId uint) ([]entity.ApiKey, error) {
+	keys, err := s.apiKeys.ListActive(ctx, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.ApiKey, len(keys))
+	for i, key := range keys {
+		res[i] = ApiKeyFromModel(key)
+	}
+	return res, nil
+}
+
+func (s *ApiKeyService) Revoke(ctx context.Context, userId, id uint) error {
+	err := s.apiKeys.Revoke(ctx, userId, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrApiKeyNotFound
+	}
+	return err
+}
+
+// Authenticate returns the active key matching a plaintext api key.
+func (s *ApiKeyService) Authenticate(ctx context.Context, apiKey string) (*entity.ApiKey, error) {
+	prefix, err := token.ParseApiKey(apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := s.apiKeys.GetActiveByPrefix(ctx, prefix)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, token.ErrorInvalidApiKey
+		}
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(token.HashApiKey(apiKey))) != 1 {
+		return nil, token.ErrorInvalidApiKey
+	}
+	if key.ExpiresAt != nil && time.Now().After(*key.ExpiresAt) {
+		return nil, ErrApiKeyExpired
+	}
+
+	res := ApiKeyFromModel(*key)
+	return &res, nil
+}
+
+// MarkUsed records the key's last use, at most once per lastUsedResolution.
+func (s *ApiKeyService) MarkUsed(ctx context.Context, key *entity.ApiKey) error {
+	if key.LastUsedAt != nil && time.Since(*key.LastUsedAt) <= lastUsedResolution {
+		return nil
+	}
+	return s.apiKeys.TouchLastUsed(ctx, key.Id, time.Now())
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/repository"
+)
+
+type BookService struct {
+	books    repository.BookRepository
+	users    repository.UserRepository
+	cart     repository.CartRepository
+	readList repository.ReadListRepository
+}
+
+// Publish stores a new book by the given author.
+func (s *BookService) Publish(ctx context.Context, authorId uint, book entity.Book) (*entity.Book, error) {
+	book.Author = entity.User{Id: authorId}
+	arg := BookToModel(book)
+	if err := s.books.Create(ctx, &arg); err != nil {
+		return nil, err
+	}
+
+	res := BookFromModel(arg)
+	return &res, nil
+}
+
+// Get, List and ListAuthors serve the public catalog, which may lag behind
+// writes, so they read from a replica when one is configured.
+func (s *BookService) Get(ctx context.Context, id uint) (*entity.Book, error) {
+	book, err := s.books.GetByID(repository.FromReplica(ctx), id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+
+	res := BookFromModel(*book)
+	return &res, nil
+}
+
+func (s *BookService) List(ctx context.Context, filter repository.BookFilter) ([]entity.Book, error) {
+	books, err := s.books.List(repository.FromReplica(ctx), filter)
+	if err != nil {
+		return nil, err
+	}
+	return BooksFromModels(books), nil
+}
+
+func (s *BookService) ListByAuthor(ctx context.Context, authorId uint, limit, offset int) ([]entity.Book, error) {
+	books, err := s.books.ListByAuthor(ctx, authorId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return BooksFromModels(books), nil
+}
+
+// Sales reports, for a page of the author's books, how many carts and read
+// lists hold each of them.
+func (s *BookService) Sales(ctx context.Context, authorId uint, limit, offset int) ([]entity.BookSales, error) {
+	books, err := s.books.ListByAuthor(ctx, authorId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, len(books))
+	for i, book := range books {
+		ids[i] = book.ID
+	}
+	inCarts, err := s.cart.CountByBook(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	inReadLists, err := s.readList.CountByBook(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.BookSales, len(books))
+	for i, book := range books {
+		res[i] = entity.BookSales{
+			Book:        BookFromModel(book),
+			InCarts:     inCarts[book.ID],
+			InReadLists: inReadLists[book.ID],
+		}
+	}
+	return res, nil
+}
+
+// Update changes the non-zero fields of one of the author's books.
+func (s *BookService) Update(ctx context.Context, authorId uint, book entity.Book) (*entity.Book, error) {
+	book.Author = entity.User{Id: authorId}
+	arg := BookToModel(book)
+	res, err := s.books.Update(ctx, authorId, &arg)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrBookNotFound
+		}
+		return nil, err
+	}
+
+	updated := BookFromModel(*res)
+	return &updated, nil
+}
+
+func (s *BookService) Delete(ctx context.Context, authorId, id uint) error {
+	err := s.books.Delete(ctx, authorId, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrBookNotFound
+	}
+	return err
+}
+
+func (s *BookService) ListAuthors(ctx context.Context, filter repository.AuthorFilter) ([]entity.Author, error) {
+	authors, err := s.users.ListAuthors(repository.FromReplica(ctx), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.Author, len(authors))
+	for i, author := range authors {
+		res[i] = AuthorFromModel(author)
+	}
+	return res, nil
+}
+
+// Owns reports whether the user owns the book. Books can't be bought yet, so
+// users only own the books they published.
+func (s *BookService) Owns(ctx context.Context, userId, bookId uint) (bool, error) {
+	book, err := s.books.GetByID(ctx, bookId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return false, ErrBookNotFound
+		}
+		return false, err
+	}
+	return book.AuthorID == userId, nil
+}
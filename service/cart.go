@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/repository"
+)
+
+type CartService struct {
+	cart  repository.CartRepository
+	books repository.BookRepository
+	work  repository.UnitOfWork
+}
+
+// Add relies on the unique (user_id, book_id) index to reject a book that is
+// already in the cart, concurrent requests can't both pass a pre-check.
+func (s *CartService) Add(ctx context.Context, userId, bookId uint) (*entity.CartItem, error) {
+	var item models.CartItem
+	err := s.work.Run(ctx, func(repos repository.Repositories) error {
+		book, err := repos.Books.GetByID(ctx, bookId)
+		if err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrBookNotFound
+			}
+			return err
+		}
+
+		item = models.CartItem{
+			UserID: userId,
+			BookID: book.ID,
+		}
+		if err := repos.Cart.Add(ctx, &item); err != nil {
+			if errors.Is(err, repository.ErrDuplicate) {
+				return ErrAlreadyInCart
+			}
+			return err
+		}
+		item.Book = *book
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := CartItemFromModel(item)
+	return &res, nil
+}
+
+// Contains reports which of the books are in the user's cart.
+func (s *CartService) Contains(ctx context.Context, userId uint, bookIds []uint) (map[uint]bool, error) {
+	ids, err := s.cart.Contains(ctx, userId, bookIds)
+	if err != nil {
+		return nil, err
+	}
+	return idSet(ids), nil
+}
+
+func (s *CartService) List(ctx context.Context, userId uint, limit, offset int) ([]entity.CartItem, error) {
+	items, err := s.cart.List(ctx, userId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.CartItem, len(items))
+	for i, item := range items {
+		res[i] = CartItemFromModel(item)
+	}
+	return res, nil
+}
+
+func (s *CartService) Remove(ctx context.Context, userId, bookId uint) error {
+	book, err := s.books.GetByID(ctx, bookId)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrBookNotFound
+		}
+		return err
+	}
+	return s.cart.Remove(ctx, userId, book.ID)
+}
+
+func (s *CartService) Clear(ctx context.Context, userId uint) error {
+	return s.cart.Clear(ctx, userId)
+}
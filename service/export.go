@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/jobs"
+	"github.com/zura-t/bookstore_fiber/repository"
+)
+
+type ExportService struct {
+	exports  repository.DataExportRepository
+	exporter *jobs.DataExporter
+}
+
+// Request starts building a personal data export for the user, or returns
+// the one that is already being built.
+func (s *ExportService) Request(ctx context.Context, userId uint) (*entity.DataExport, error) {
+	export, err := s.exporter.Request(ctx, userId)
+	if err != nil {
+		if errors.Is(err, jobs.ErrExportQueueFull) {
+			return nil, ErrExportBusy
+		}
+		return nil, err
+	}
+
+	res := DataExportFromModel(*export)
+	return &res, nil
+}
+
+func (s *ExportService) Get(ctx context.Context, userId uint, id string) (*entity.DataExport, error) {
+	export, err := s.exports.Get(ctx, userId, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrExportNotFound
+		}
+		return nil, err
+	}
+
+	res := DataExportFromModel(*export)
+	return &res, nil
+}
+
+// Download returns an export whose archive can be downloaded.
+func (s *ExportService) Download(ctx context.Context, userId uint, id string) (*entity.DataExport, error) {
+	export, err := s.Get(ctx, userId, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if export.Status == entity.ExportExpired || (export.ExpiresAt != nil && time.Now().After(*export.ExpiresAt)) {
+		return nil, ErrExportExpired
+	}
+	if export.Status != entity.ExportReady {
+		return nil, ErrExportNotReady.WithMessage(fmt.Sprintf("Export is not ready, status is %s", export.Status)).WithParams(export.Status)
+	}
+	return export, nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/oidc"
+	"github.com/zura-t/bookstore_fiber/repository"
+)
+
+// LoginState is the per-login secret state of an authorization code flow.
+type LoginState struct {
+	State        string
+	Provider     string
+	CodeVerifier string
+	Nonce        string
+	ExpiresAt    time.Time
+	// Restore is set for logins that restore a deleted account.
+	Restore bool
+}
+
+type IdentityService struct {
+	config     config.Config
+	identities repository.IdentityRepository
+	users      repository.UserRepository
+}
+
+// NewLoginState stores a fresh state, nonce and PKCE verifier for a login
+// with provider.
+func (s *IdentityService) NewLoginState(ctx context.Context, provider string, restore bool) (*LoginState, error) {
+	state, err := oidc.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := oidc.RandomToken(32)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := oidc.NewCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	arg := models.OidcState{
+		State:        state,
+		Provider:     provider,
+		CodeVerifier: verifier,
+		Nonce:        nonce,
+		ExpiresAt:    time.Now().Add(s.config.OidcStateDuration),
+		Restore:      restore,
+	}
+	if err := s.identities.CreateState(ctx, &arg); err != nil {
+		return nil, err
+	}
+
+	res := loginStateFromModel(arg)
+	return &res, nil
+}
+
+// TakeLoginState consumes a login state, so that it can only be used once.
+func (s *IdentityService) TakeLoginState(ctx context.Context, provider, state string) (*LoginState, error) {
+	if state == "" {
+		return nil, ErrInvalidLoginState
+	}
+
+	res, err := s.identities.TakeState(ctx, provider, state)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrInvalidLoginState
+		}
+		return nil, err
+	}
+
+	if time.Now().After(res.ExpiresAt) {
+		return nil, ErrLoginStateExpired
+	}
+
+	loginState := loginStateFromModel(*res)
+	return &loginState, nil
+}
+
+func loginStateFromModel(state models.OidcState) LoginState {
+	return LoginState{
+		State:        state.State,
+		Provider:     state.Provider,
+		CodeVerifier: state.CodeVerifier,
+		Nonce:        state.Nonce,
+		ExpiresAt:    state.ExpiresAt,
+		Restore:      state.Restore,
+	}
+}
+
+// Link finds the user for an external identity. Unknown identities are
+// linked to the user with the same verified email, or to a new user without
+// a password.
+func (s *IdentityService) Link(ctx context.Context, provider string, claims *oidc.Claims) (*entity.User, error) {
+	user, err := s.identities.GetUser(ctx, provider, claims.Subject)
+	if err == nil {
+		if user.ID == 0 || user.DeletedAt.Valid {
+			return nil, ErrAccountDeleted
+		}
+		res := UserFromModel(*user)
+		return &res, nil
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return nil, err
+	}
+
+	if claims.Email == "" || !claims.EmailVerified {
+		return nil, ErrEmailNotVerified
+	}
+
+	user, err = s.users.GetByEmail(ctx, claims.Email)
+	if errors.Is(err, repository.ErrNotFound) {
+		taken, err := s.users.EmailTaken(ctx, claims.Email)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, ErrAccountDeleted
+		}
+
+		name := claims.Name
+		if name == "" {
+			name, _, _ = strings.Cut(claims.Email, "@")
+		}
+		user = &models.User{Email: claims.Email, Name: name}
+	} else if err != nil {
+		return nil, err
+	}
+
+	err = s.identities.Link(ctx, &models.UserIdentity{
+		Provider: provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}, user)
+	if err != nil {
+		return nil, err
+	}
+
+	res := UserFromModel(*user)
+	return &res, nil
+}
+
+// Restore undoes the deletion of the user linked to an external identity,
+// the counterpart of UserService.Restore for accounts without a password.
+func (s *IdentityService) Restore(ctx context.Context, provider string, claims *oidc.Claims) (*entity.User, error) {
+	user, err := s.identities.GetUser(ctx, provider, claims.Subject)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeletedAccountNotFound
+		}
+		return nil, err
+	}
+	if user.ID == 0 || !user.DeletedAt.Valid || user.AnonymizedAt != nil {
+		return nil, ErrDeletedAccountNotFound
+	}
+
+	if time.Since(user.DeletedAt.Time) > s.config.DeletionGracePeriod {
+		return nil, ErrRestorePeriodEnded
+	}
+
+	if err := s.users.Restore(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	res := UserFromModel(*user)
+	res.DeletedAt = time.Time{}
+	return &res, nil
+}
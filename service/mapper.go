@@ -0,0 +1,156 @@
+package service
+
+import (
+	"strings"
+	"time"
+
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/models"
+	"gorm.io/gorm"
+)
+
+// The mappers below are the only place where models and entity types meet.
+// Password hashes, join rows and other storage details never leave the
+// service package.
+
+func UserFromModel(user models.User) entity.User {
+	return entity.User{
+		Id:        user.ID,
+		Email:     user.Email,
+		Name:      user.Name,
+		IsAuthor:  user.IsAuthor,
+		Locale:    user.Locale,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		DeletedAt: user.DeletedAt.Time,
+	}
+}
+
+func UserToModel(user entity.User) models.User {
+	return models.User{
+		ID:        user.Id,
+		Email:     user.Email,
+		Name:      user.Name,
+		IsAuthor:  user.IsAuthor,
+		Locale:    user.Locale,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+		DeletedAt: deletedAt(user.DeletedAt),
+	}
+}
+
+func AuthorFromModel(user models.User) entity.Author {
+	return entity.Author{
+		User:       UserFromModel(user),
+		BooksCount: len(user.AuthorBooks),
+	}
+}
+
+// BookFromModel maps a book and its author. The author only has an id when
+// it was not preloaded.
+func BookFromModel(book models.Book) entity.Book {
+	author := UserFromModel(book.Author)
+	author.Id = book.AuthorID
+	return entity.Book{
+		Id:          book.ID,
+		Title:       book.Title,
+		Description: book.Description,
+		Price:       book.Price,
+		File:        book.File,
+		Author:      author,
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+		DeletedAt:   book.DeletedAt.Time,
+	}
+}
+
+func BookToModel(book entity.Book) models.Book {
+	return models.Book{
+		ID:          book.Id,
+		Title:       book.Title,
+		Description: book.Description,
+		Price:       book.Price,
+		File:        book.File,
+		AuthorID:    book.Author.Id,
+		CreatedAt:   book.CreatedAt,
+		UpdatedAt:   book.UpdatedAt,
+		DeletedAt:   deletedAt(book.DeletedAt),
+	}
+}
+
+func BooksFromModels(books []models.Book) []entity.Book {
+	res := make([]entity.Book, len(books))
+	for i, book := range books {
+		res[i] = BookFromModel(book)
+	}
+	return res
+}
+
+func CartItemFromModel(item models.CartItem) entity.CartItem {
+	return entity.CartItem{
+		Id:        item.ID,
+		UserId:    item.UserID,
+		Book:      BookFromModel(item.Book),
+		CreatedAt: item.CreatedAt,
+		UpdatedAt: item.UpdatedAt,
+	}
+}
+
+func ReadListEntryFromModel(entry models.UserBook) entity.ReadListEntry {
+	return entity.ReadListEntry{
+		Book:    BookFromModel(entry.Book),
+		AddedAt: entry.CreatedAt,
+	}
+}
+
+func ApiKeyFromModel(key models.ApiKey) entity.ApiKey {
+	user := UserFromModel(key.User)
+	user.Id = key.UserID
+	return entity.ApiKey{
+		Id:         key.ID,
+		User:       user,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     key.ScopeList(),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func ApiKeyToModel(key entity.ApiKey) models.ApiKey {
+	return models.ApiKey{
+		ID:         key.Id,
+		UserID:     key.User.Id,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     strings.Join(key.Scopes, " "),
+		ExpiresAt:  key.ExpiresAt,
+		LastUsedAt: key.LastUsedAt,
+		CreatedAt:  key.CreatedAt,
+	}
+}
+
+func DataExportFromModel(export models.DataExport) entity.DataExport {
+	return entity.DataExport{
+		Id:        export.ID,
+		UserId:    export.UserID,
+		Status:    export.Status,
+		Error:     export.Error,
+		File:      export.File,
+		CreatedAt: export.CreatedAt,
+		ExpiresAt: export.ExpiresAt,
+	}
+}
+
+func deletedAt(t time.Time) gorm.DeletedAt {
+	return gorm.DeletedAt{Time: t, Valid: !t.IsZero()}
+}
+
+func idSet(ids []uint) map[uint]bool {
+	set := make(map[uint]bool, len(ids))
+	for _, id := range ids {
+		set[id] = true
+	}
+	return set
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/repository"
+)
+
+type ReadListService struct {
+	readList repository.ReadListRepository
+	work     repository.UnitOfWork
+}
+
+func (s *ReadListService) Add(ctx context.Context, userId, bookId uint) error {
+	return s.work.Run(ctx, func(repos repository.Repositories) error {
+		if _, err := repos.Books.GetByID(ctx, bookId); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return ErrBookNotFound
+			}
+			return err
+		}
+
+		err := repos.ReadList.Add(ctx, userId, bookId)
+		if errors.Is(err, repository.ErrDuplicate) {
+			return ErrAlreadyInReadList
+		}
+		return err
+	})
+}
+
+// Contains reports which of the books are in the user's read list.
+func (s *ReadListService) Contains(ctx context.Context, userId uint, bookIds []uint) (map[uint]bool, error) {
+	ids, err := s.readList.Contains(ctx, userId, bookIds)
+	if err != nil {
+		return nil, err
+	}
+	return idSet(ids), nil
+}
+
+func (s *ReadListService) List(ctx context.Context, userId uint, limit, offset int) ([]entity.ReadListEntry, error) {
+	entries, err := s.readList.List(ctx, userId, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.ReadListEntry, len(entries))
+	for i, entry := range entries {
+		res[i] = ReadListEntryFromModel(entry)
+	}
+	return res, nil
+}
+
+func (s *ReadListService) Remove(ctx context.Context, userId, bookId uint) error {
+	return s.readList.Remove(ctx, userId, bookId)
+}
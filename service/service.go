@@ -0,0 +1,63 @@
+// Package service implements the bookstore's business operations on entity
+// types. Handlers translate HTTP requests into service calls and service
+// errors into responses, repositories are only used from here.
+package service
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/apperror"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/jobs"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/token"
+)
+
+// Errors returned to handlers. The codes are part of the API, clients match
+// on them, so they must not change.
+var (
+	ErrUserNotFound           = apperror.New(apperror.NotFound, "user_not_found", "User not found")
+	ErrEmailTaken             = apperror.New(apperror.Conflict, "email_taken", "User with this email already exists")
+	ErrIncorrectPassword      = apperror.New(apperror.Unauthorized, "incorrect_password", "Error incorrect password")
+	ErrPasswordTooShort       = apperror.New(apperror.Invalid, "password_too_short", "Password is too short")
+	ErrPasswordTooLong        = apperror.New(apperror.Invalid, "password_too_long", "Password is too long")
+	ErrPasswordBreached       = apperror.New(apperror.Invalid, "password_breached", "Password has appeared in a data breach, choose another one")
+	ErrDeletedAccountNotFound = apperror.New(apperror.NotFound, "deleted_account_not_found", "Deleted account not found")
+	ErrRestorePeriodEnded     = apperror.New(apperror.Gone, "restore_period_ended", "The restore period for this account has ended")
+	ErrBookNotFound           = apperror.New(apperror.NotFound, "book_not_found", "Book not found")
+	ErrAlreadyInCart          = apperror.New(apperror.Conflict, "already_in_cart", "You've already added this book to cart")
+	ErrAlreadyInReadList      = apperror.New(apperror.Conflict, "already_in_read_list", "You've already added this book to read list")
+	ErrUnknownScope           = apperror.New(apperror.Invalid, "unknown_scope", "Unknown scope")
+	ErrApiKeyNotFound         = apperror.New(apperror.NotFound, "api_key_not_found", "Api key not found")
+	ErrApiKeyExpired          = apperror.New(apperror.Unauthorized, "api_key_expired", "api key has expired")
+	ErrInvalidLoginState      = apperror.New(apperror.Invalid, "invalid_login_state", "Invalid login state")
+	ErrLoginStateExpired      = apperror.New(apperror.Invalid, "login_state_expired", "Login state has expired")
+	ErrEmailNotVerified       = apperror.New(apperror.Forbidden, "email_not_verified", "Email is not verified by the identity provider")
+	ErrAccountDeleted         = apperror.New(apperror.Forbidden, "account_deleted", "This account has been deleted")
+	ErrExportNotFound         = apperror.New(apperror.NotFound, "export_not_found", "Export not found")
+	ErrExportExpired          = apperror.New(apperror.Gone, "export_expired", "Export has expired")
+	ErrExportNotReady         = apperror.New(apperror.Conflict, "export_not_ready", "Export is not ready")
+	ErrExportBusy             = apperror.New(apperror.Unavailable, "export_busy", "Too many exports are being built, try again later")
+)
+
+type Services struct {
+	Users      *UserService
+	Books      *BookService
+	Cart       *CartService
+	ReadList   *ReadListService
+	ApiKeys    *ApiKeyService
+	Identities *IdentityService
+	Exports    *ExportService
+}
+
+func New(log *logrus.Logger, config config.Config, repos repository.Repositories, hasher *pkg.PasswordHasher, policy *pkg.PasswordPolicy, exporter *jobs.DataExporter) Services {
+	return Services{
+		Users:      &UserService{log, config, repos.Users, hasher, policy},
+		Books:      &BookService{repos.Books, repos.Users, repos.Cart, repos.ReadList},
+		Cart:       &CartService{repos.Cart, repos.Books, repos.UnitOfWork},
+		ReadList:   &ReadListService{repos.ReadList, repos.UnitOfWork},
+		ApiKeys:    &ApiKeyService{repos.ApiKeys, token.GenerateApiKey},
+		Identities: &IdentityService{config, repos.Identities, repos.Users},
+		Exports:    &ExportService{repos.Exports, exporter},
+	}
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/logger"
+	"github.com/zura-t/bookstore_fiber/models"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+	"github.com/zura-t/bookstore_fiber/tracing"
+)
+
+type UserService struct {
+	log    *logrus.Logger
+	config config.Config
+	users  repository.UserRepository
+	hasher *pkg.PasswordHasher
+	policy *pkg.PasswordPolicy
+}
+
+// Register creates a user after checking the password policy. Password
+// policy failures are returned as one of the ErrPassword errors wrapping the
+// pkg.PolicyViolation.
+func (s *UserService) Register(ctx context.Context, name, email, password string) (*entity.User, error) {
+	if err := s.policy.Validate(password); err != nil {
+		return nil, policyError(err)
+	}
+
+	hashedPassword, err := s.hash(ctx, password)
+	if err != nil {
+		return nil, err
+	}
+
+	user := models.User{
+		Email:    email,
+		Name:     name,
+		Password: hashedPassword,
+	}
+	// The unique email index also covers soft deleted users, so it rejects
+	// every email EmailTaken would.
+	if err := s.users.Create(ctx, &user); err != nil {
+		if errors.Is(err, repository.ErrDuplicate) {
+			return nil, ErrEmailTaken
+		}
+		return nil, err
+	}
+
+	res := UserFromModel(user)
+	return &res, nil
+}
+
+// Authenticate checks the user's password and upgrades an outdated password
+// hash on success.
+func (s *UserService) Authenticate(ctx context.Context, email, password string) (*entity.User, error) {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	needsRehash, err := s.verify(ctx, password, user.Password)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %s", ErrIncorrectPassword, err)
+	}
+
+	if needsRehash {
+		s.rehashPassword(ctx, user.ID, password)
+	}
+
+	res := UserFromModel(*user)
+	return &res, nil
+}
+
+// rehashPassword upgrades an outdated password hash after a successful login.
+// Failures are only logged, the login itself has already succeeded.
+func (s *UserService) rehashPassword(ctx context.Context, userId uint, password string) {
+	hashedPassword, err := s.hash(ctx, password)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warn(err)
+		return
+	}
+
+	err = s.users.UpdatePassword(ctx, userId, hashedPassword)
+	if err != nil {
+		logger.FromContext(ctx, s.log).Warn(err)
+	}
+}
+
+func (s *UserService) Get(ctx context.Context, id uint) (*entity.User, error) {
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	res := UserFromModel(*user)
+	return &res, nil
+}
+
+func (s *UserService) List(ctx context.Context) ([]entity.User, error) {
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity.User, len(users))
+	for i, user := range users {
+		res[i] = UserFromModel(user)
+	}
+	return res, nil
+}
+
+// UpdateProfile changes the name and the preferred locale, an empty locale
+// keeps the current one.
+func (s *UserService) UpdateProfile(ctx context.Context, id uint, name, locale string) (*entity.User, error) {
+	user, err := s.users.UpdateProfile(ctx, id, name, locale)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	res := UserFromModel(*user)
+	return &res, nil
+}
+
+func (s *UserService) ChangePassword(ctx context.Context, id uint, oldPassword, newPassword string) error {
+	if err := s.policy.Validate(newPassword); err != nil {
+		return policyError(err)
+	}
+
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+
+	if _, err := s.verify(ctx, oldPassword, user.Password); err != nil {
+		return fmt.Errorf("%w, %s", ErrIncorrectPassword, err)
+	}
+
+	hashedPassword, err := s.hash(ctx, newPassword)
+	if err != nil {
+		return err
+	}
+	return s.users.UpdatePassword(ctx, user.ID, hashedPassword)
+}
+
+func (s *UserService) BecomeAuthor(ctx context.Context, id uint) error {
+	return s.users.SetAuthor(ctx, id)
+}
+
+// IsAuthor reports whether the user may publish books.
+func (s *UserService) IsAuthor(ctx context.Context, id uint) (bool, error) {
+	user, err := s.Get(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return user.IsAuthor, nil
+}
+
+// CheckActive returns ErrAccountDeleted for deleted users, so that the
+// sessions of an account end when it is deleted.
+func (s *UserService) CheckActive(ctx context.Context, id uint) error {
+	_, err := s.users.GetByID(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrAccountDeleted
+	}
+	return err
+}
+
+// Delete soft deletes the user and returns the time until which the account
+// can be restored.
+func (s *UserService) Delete(ctx context.Context, id uint) (time.Time, error) {
+	if err := s.users.Delete(ctx, id); err != nil {
+		return time.Time{}, err
+	}
+	return time.Now().Add(s.config.DeletionGracePeriod), nil
+}
+
+// Restore undoes Delete while the account is still within the deletion grace
+// period and has not been anonymized.
+func (s *UserService) Restore(ctx context.Context, email, password string) (*entity.User, error) {
+	user, err := s.users.GetDeletedByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return nil, ErrDeletedAccountNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := s.verify(ctx, password, user.Password); err != nil {
+		return nil, fmt.Errorf("%w, %s", ErrIncorrectPassword, err)
+	}
+
+	if time.Since(user.DeletedAt.Time) > s.config.DeletionGracePeriod {
+		return nil, ErrRestorePeriodEnded
+	}
+
+	if err := s.users.Restore(ctx, user.ID); err != nil {
+		return nil, err
+	}
+
+	res := UserFromModel(*user)
+	res.DeletedAt = time.Time{}
+	return &res, nil
+}
+
+// hash and verify run the password hasher in spans, Argon2 is deliberately
+// slow and takes a large share of every login and registration.
+func (s *UserService) hash(ctx context.Context, password string) (hashedPassword string, err error) {
+	_, span := tracing.Start(ctx, "password.hash")
+	defer func() { tracing.End(span, err) }()
+	return s.hasher.Hash(password)
+}
+
+func (s *UserService) verify(ctx context.Context, password, hashedPassword string) (needsRehash bool, err error) {
+	_, span := tracing.Start(ctx, "password.verify")
+	defer func() { tracing.End(span, err) }()
+	return s.hasher.Verify(password, hashedPassword)
+}
+
+// policyError reports a password policy violation with the policy's message.
+func policyError(err error) error {
+	var violation pkg.PolicyViolation
+	if !errors.As(err, &violation) {
+		return err
+	}
+	switch violation.Reason {
+	case pkg.ViolationTooShort:
+		return ErrPasswordTooShort.WithMessage(violation.Error()).WithParams(strconv.Itoa(violation.Limit)).Wrap(err)
+	case pkg.ViolationTooLong:
+		return ErrPasswordTooLong.WithMessage(violation.Error()).WithParams(strconv.Itoa(violation.Limit)).Wrap(err)
+	}
+	return ErrPasswordBreached.Wrap(err)
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/config"
+	"github.com/zura-t/bookstore_fiber/entity"
+	"github.com/zura-t/bookstore_fiber/pkg"
+	"github.com/zura-t/bookstore_fiber/repository"
+)
+
+func newTestServices() Services {
+	hasher := pkg.NewPasswordHasher(pkg.Argon2Params{Memory: 1024, Iterations: 1, Parallelism: 1})
+	return New(logrus.New(), config.Config{}, repository.NewMemoryRepositories(), hasher, pkg.NewPasswordPolicy(8, 64), nil)
+}
+
+func TestUserService(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+
+	_, err := services.Users.Register(ctx, "Ann", "ann@example.com", "short")
+	var violation pkg.PolicyViolation
+	require.ErrorAs(t, err, &violation)
+
+	user, err := services.Users.Register(ctx, "Ann", "ann@example.com", "long enough password")
+	require.NoError(t, err)
+	require.NotZero(t, user.Id)
+
+	_, err = services.Users.Register(ctx, "Ann", "ann@example.com", "long enough password")
+	require.ErrorIs(t, err, ErrEmailTaken)
+
+	_, err = services.Users.Authenticate(ctx, user.Email, "wrong password")
+	require.ErrorIs(t, err, ErrIncorrectPassword)
+
+	err = services.Users.ChangePassword(ctx, user.Id, "long enough password", "another password")
+	require.NoError(t, err)
+	authenticated, err := services.Users.Authenticate(ctx, user.Email, "another password")
+	require.NoError(t, err)
+	require.Equal(t, user.Id, authenticated.Id)
+}
+
+func TestBookAndCartServices(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+
+	author, err := services.Users.Register(ctx, "Ann", "ann@example.com", "long enough password")
+	require.NoError(t, err)
+	require.NoError(t, services.Users.BecomeAuthor(ctx, author.Id))
+
+	book, err := services.Books.Publish(ctx, author.Id, entity.Book{Title: "Dune", Price: 10})
+	require.NoError(t, err)
+	require.Equal(t, author.Id, book.Author.Id)
+
+	item, err := services.Cart.Add(ctx, author.Id, book.Id)
+	require.NoError(t, err)
+	require.Equal(t, "Dune", item.Book.Title)
+
+	_, err = services.Cart.Add(ctx, author.Id, book.Id)
+	require.ErrorIs(t, err, ErrAlreadyInCart)
+
+	_, err = services.Cart.Add(ctx, author.Id, book.Id+1)
+	require.ErrorIs(t, err, ErrBookNotFound)
+}
+
+func TestApiKeyPrefixCollision(t *testing.T) {
+	ctx := context.Background()
+	services := newTestServices()
+
+	// The second key first gets the prefix of the first one.
+	prefixes := []string{"0000aaaa", "0000aaaa", "0000bbbb"}
+	services.ApiKeys.generate = func() (string, string, string, error) {
+		prefix := prefixes[0]
+		prefixes = prefixes[1:]
+		return "bks_" + prefix + "_secret", prefix, prefix + "-hash", nil
+	}
+
+	first, _, err := services.ApiKeys.Create(ctx, 1, "ci", nil, 0)
+	require.NoError(t, err)
+	second, plaintext, err := services.ApiKeys.Create(ctx, 1, "deploy", nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, "0000aaaa", first.Prefix)
+	require.Equal(t, "0000bbbb", second.Prefix)
+	require.Equal(t, "bks_0000bbbb_secret", plaintext)
+}
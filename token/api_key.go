@@ -0,0 +1,65 @@
+package token
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+const apiKeyPrefix = "bks_"
+
+const (
+	ScopeCatalogRead = "catalog:read"
+	ScopeBooksManage = "books:manage"
+	ScopeSalesRead   = "sales:read"
+)
+
+var Scopes = []string{ScopeCatalogRead, ScopeBooksManage, ScopeSalesRead}
+
+var ErrorInvalidApiKey = errors.New("api key is invalid")
+
+// GenerateApiKey returns a new key of the form bks_<prefix>_<secret>. Only the
+// prefix and the hash of the whole key are meant to be stored.
+func GenerateApiKey() (key string, prefix string, hash string, err error) {
+	b := make([]byte, 4+24)
+	if _, err = rand.Read(b); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(b[:4])
+	key = apiKeyPrefix + prefix + "_" + hex.EncodeToString(b[4:])
+	return key, prefix, HashApiKey(key), nil
+}
+
+func IsApiKey(key string) bool {
+	return strings.HasPrefix(key, apiKeyPrefix)
+}
+
+// ParseApiKey returns the lookup prefix of a key.
+func ParseApiKey(key string) (string, error) {
+	rest, ok := strings.CutPrefix(key, apiKeyPrefix)
+	if !ok {
+		return "", ErrorInvalidApiKey
+	}
+	prefix, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(prefix) != 8 || len(secret) != 48 {
+		return "", ErrorInvalidApiKey
+	}
+	return prefix, nil
+}
+
+func HashApiKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func ValidScope(scope string) bool {
+	for _, s := range Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
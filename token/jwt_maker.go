@@ -18,9 +18,7 @@ type JwtMaker struct {
 func NewJwtMaker(log *logrus.Logger, secretKey string) (*JwtMaker, error) {
 	if len(secretKey) < minSecretKeySize {
 		err := fmt.Errorf("an invalid key size: must be at least %d characters", minSecretKeySize)
-		log.WithFields(logrus.Fields{
-			"level": "Error",
-		}).Error(err)
+		log.Error(err)
 		return nil, err
 	}
 	return &JwtMaker{secretKey}, nil
@@ -0,0 +1,68 @@
+package tracing
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/require"
+	"github.com/zura-t/bookstore_fiber/middlewares/logger"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/gorm"
+)
+
+const (
+	traceID     = "4bf92f3577b34da6a3ce929d0e0e4736"
+	traceparent = "00-" + traceID + "-00f067aa0ba902b7-01"
+)
+
+func TestTracing(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, Instrument(db))
+
+	var out bytes.Buffer
+	log := logrus.New()
+	log.SetOutput(&out)
+
+	app := fiber.New()
+	app.Use(Middleware())
+	app.Use(logger.New(log))
+	app.Get("/books/:id", func(c *fiber.Ctx) error {
+		var one int
+		if err := db.WithContext(c.UserContext()).Raw("SELECT 1").Scan(&one).Error; err != nil {
+			return err
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/books/1", nil)
+	req.Header.Set("traceparent", traceparent)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	query, server := spans[0], spans[1]
+
+	require.Equal(t, "GET /books/:id", server.Name())
+	require.Equal(t, traceID, server.SpanContext().TraceID().String())
+	require.Equal(t, "db.row", query.Name())
+	require.Equal(t, server.SpanContext().SpanID(), query.Parent().SpanID())
+
+	require.Contains(t, out.String(), "trace_id="+traceID)
+}